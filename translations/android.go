@@ -1,10 +1,12 @@
 package main
 
 import (
+	"bytes"
 	"encoding/xml"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 )
 
@@ -77,6 +79,10 @@ func ImportFromAndroid(tm *Translations, baseDirectory string) error {
 			// Handle regional variants like values-de-rAT -> de-AT
 			lang = strings.Replace(lang, "-r", "_", 1)
 		}
+		if isPseudoLocale(lang) {
+			fmt.Printf("Skipping pseudo locale %s\n", dir)
+			continue
+		}
 
 		file := filepath.Join(androidResPath, dir, "strings.xml")
 		data, err := os.ReadFile(file)
@@ -96,7 +102,7 @@ func ImportFromAndroid(tm *Translations, baseDirectory string) error {
 
 		// Process regular strings
 		for _, str := range resources.Strings {
-			tm.SetTranslation("android", str.Name, lang, str.Value, "")
+			tm.SetTranslation("android", str.Name, lang, unescapeAndroidString(str.Value), "")
 			importCount++
 		}
 
@@ -107,11 +113,11 @@ func ImportFromAndroid(tm *Translations, baseDirectory string) error {
 
 			for _, item := range plural.Items {
 				if item.Quantity == "one" {
-					oneValue = item.Value
+					oneValue = unescapeAndroidString(item.Value)
 				} else {
 					// Android has many quantity types (zero, one, two, few, many, other)
 					// but we map them all to "other" for simplicity
-					otherValue = item.Value
+					otherValue = unescapeAndroidString(item.Value)
 				}
 			}
 
@@ -153,19 +159,7 @@ func ExportToAndroid(tm *Translations, baseDirectory string) error {
 			continue
 		}
 
-		// Determine the values directory name
-		dirName := "values"
-		if lang != "en" {
-			// Handle regional variants like de-AT -> values-de-rAT
-			if strings.Contains(lang, "-") {
-				parts := strings.Split(lang, "-")
-				dirName = fmt.Sprintf("values-%s-r%s", parts[0], strings.ToUpper(parts[1]))
-			} else {
-				dirName = "values-" + lang
-			}
-		}
-
-		dirPath := filepath.Join(androidResPath, dirName)
+		dirPath := filepath.Join(androidResPath, androidValuesDir(lang))
 
 		// Create directory if it doesn't exist
 		if err := os.MkdirAll(dirPath, 0755); err != nil {
@@ -201,12 +195,12 @@ func ExportToAndroid(tm *Translations, baseDirectory string) error {
 
 				pluralResource.Items = append(pluralResource.Items, PluralItem{
 					Quantity: "one",
-					Value:    pluralValues.One[lang],
+					Value:    escapeAndroidString(pluralValues.One[lang]),
 				})
 
 				pluralResource.Items = append(pluralResource.Items, PluralItem{
 					Quantity: "other",
-					Value:    pluralValues.Other[lang],
+					Value:    escapeAndroidString(pluralValues.Other[lang]),
 				})
 
 				if len(pluralResource.Items) > 0 {
@@ -217,7 +211,7 @@ func ExportToAndroid(tm *Translations, baseDirectory string) error {
 			} else if !processedPlurals[singularKey] {
 				resources.Strings = append(resources.Strings, StringElement{
 					Name:  trans.Key,
-					Value: values,
+					Value: escapeAndroidString(values),
 				})
 			}
 		}
@@ -233,6 +227,9 @@ func ExportToAndroid(tm *Translations, baseDirectory string) error {
 			return fmt.Errorf("error generating XML for %s: %v", lang, err)
 		}
 
+		// Escaped quotes are readable without the entities encoding/xml writes for them
+		xmlData = bytes.ReplaceAll(bytes.ReplaceAll(xmlData, []byte("&#34;"), []byte(`"`)), []byte("&#39;"), []byte("'"))
+
 		// Add XML header
 		xmlContent := []byte(xml.Header + string(xmlData))
 
@@ -245,5 +242,70 @@ func ExportToAndroid(tm *Translations, baseDirectory string) error {
 		fmt.Printf("Exported Android translations to %s\n", filePath)
 	}
 
+	// Pseudo locales of an earlier export, also under the underscore name used before
+	staleDirs := func(lang string) []string {
+		return []string{filepath.Join(androidResPath, androidValuesDir(lang)), filepath.Join(androidResPath, "values-"+lang)}
+	}
+	err := removeStalePseudoLocales(tm, func(lang string) []string {
+		files := make([]string, 0)
+		for _, dir := range staleDirs(lang) {
+			files = append(files, filepath.Join(dir, "strings.xml"))
+		}
+		return files
+	})
+	if err != nil {
+		return err
+	}
+	for _, lang := range []string{PseudoLocaleAccented, PseudoLocaleBidi} {
+		if !slices.Contains(tm.Languages, lang) {
+			for _, dir := range staleDirs(lang) {
+				// Only removed if the export left it empty
+				_ = os.Remove(dir)
+			}
+		}
+	}
+
 	return nil
 }
+
+// androidValuesDir returns the values directory of a language. Regional variants keep the
+// locale name (values-de_AT), the pseudo locales need the Android qualifier (values-en-rXA).
+func androidValuesDir(lang string) string {
+	switch {
+	case lang == "en":
+		return "values"
+	case isPseudoLocale(lang):
+		parts := strings.Split(lang, "_")
+		return fmt.Sprintf("values-%s-r%s", parts[0], parts[1])
+	case strings.Contains(lang, "-"):
+		// Handle regional variants like de-AT -> values-de-rAT
+		parts := strings.Split(lang, "-")
+		return fmt.Sprintf("values-%s-r%s", parts[0], strings.ToUpper(parts[1]))
+	}
+	return "values-" + lang
+}
+
+// escapeAndroidString escapes quotes, which Android would otherwise strip from the string,
+// and a leading @ or ?, which would make it a resource reference
+func escapeAndroidString(value string) string {
+	var sb strings.Builder
+	for i, r := range value {
+		if (r == '"' || r == '\'') && (i == 0 || value[i-1] != '\\') {
+			sb.WriteRune('\\')
+		}
+		if (r == '@' || r == '?') && i == 0 {
+			sb.WriteRune('\\')
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// unescapeAndroidString reverses escapeAndroidString
+func unescapeAndroidString(value string) string {
+	value = strings.NewReplacer(`\"`, `"`, `\'`, "'").Replace(value)
+	if strings.HasPrefix(value, `\@`) || strings.HasPrefix(value, `\?`) {
+		value = value[1:]
+	}
+	return value
+}
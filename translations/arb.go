@@ -0,0 +1,253 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// arbMetadata is the "@key" object of an ARB file
+type arbMetadata struct {
+	Description  string          `json:"description,omitempty"`
+	Placeholders json.RawMessage `json:"placeholders,omitempty"`
+}
+
+// arbLocalePattern matches a locale at the end of an ARB file name (app_localizations_de_AT → de_AT)
+var arbLocalePattern = regexp.MustCompile(`(?:^|_)(` + localePattern + `)$`)
+
+// ImportFromARB imports translations from Flutter .arb files named <prefix>_<lang>.arb
+func ImportFromARB(tm *Translations, app, baseDirectory string) error {
+	if _, err := os.Stat(baseDirectory); os.IsNotExist(err) {
+		return fmt.Errorf("ARB directory not found at %s", baseDirectory)
+	}
+
+	files, err := filepath.Glob(filepath.Join(baseDirectory, "*.arb"))
+	if err != nil {
+		return fmt.Errorf("error listing %s: %v", baseDirectory, err)
+	}
+
+	for _, file := range files {
+		if err := importFromARBFile(tm, app, file); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func importFromARBFile(tm *Translations, app, filePath string) error {
+	fmt.Printf("Importing from ARB file: %s...\n", filePath)
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("error reading %s: %v", filePath, err)
+	}
+
+	var parseData map[string]json.RawMessage
+	if err := json.NewDecoder(bytes.NewReader(data)).Decode(&parseData); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", filePath, err)
+	}
+
+	// The locale is taken from @@locale, or from the end of the file name, whatever the
+	// prefix is (app_de_AT.arb, app_localizations_de_AT.arb → de_AT)
+	lang := ""
+	if raw, ok := parseData["@@locale"]; ok {
+		_ = json.Unmarshal(raw, &lang)
+	}
+	if lang == "" {
+		name := strings.TrimSuffix(filepath.Base(filePath), ".arb")
+		match := arbLocalePattern.FindStringSubmatch(name)
+		if match == nil {
+			fmt.Printf("Warning: no locale in %s, skipping it\n", filePath)
+			return nil
+		}
+		lang = match[1]
+	}
+	if isPseudoLocale(lang) {
+		fmt.Printf("Skipping pseudo locale %s\n", filePath)
+		return nil
+	}
+	tm.EnsureLanguage(lang)
+
+	values := make(map[string]string)
+	metadata := make(map[string]arbMetadata)
+	for key, raw := range parseData {
+		if strings.HasPrefix(key, "@@") {
+			continue
+		}
+		if strings.HasPrefix(key, "@") {
+			var meta arbMetadata
+			if err := json.Unmarshal(raw, &meta); err != nil {
+				fmt.Printf("Warning: %s: skipping invalid metadata %q: %v\n", filePath, key, err)
+				continue
+			}
+			metadata[key[1:]] = meta
+			continue
+		}
+
+		var value string
+		if err := json.Unmarshal(raw, &value); err != nil {
+			fmt.Printf("Warning: %s: skipping key %q with unsupported value %s\n", filePath, key, raw)
+			continue
+		}
+		values[key] = value
+	}
+
+	singles, plurals := foldJSONPlurals(values, filePath)
+	for key, value := range singles {
+		tm.SetTranslation(app, key, lang, value, metadata[key].Description)
+		if placeholders := compactJSON(metadata[key].Placeholders); placeholders != "" {
+			tm.SetPlaceholders(app, key, placeholders)
+		}
+	}
+	for key, value := range plurals {
+		tm.SetPluralTranslation(app, key, lang, value[0], value[1], metadata[key].Description)
+		if placeholders := compactJSON(metadata[key].Placeholders); placeholders != "" {
+			tm.SetPlaceholders(app, key+".singular", placeholders)
+			tm.SetPlaceholders(app, key+".plural", placeholders)
+		}
+	}
+	return nil
+}
+
+// ExportToARB exports translations to Flutter .arb files named <prefix>_<lang>.arb.
+// Descriptions and placeholder metadata are written to the English template file only.
+func ExportToARB(tm *Translations, app, baseDirectory, prefix string, opts JSONOptions) error {
+	tm.Sort()
+
+	translations := tm.GetTranslationsForApp(app)
+	if len(translations) == 0 {
+		fmt.Printf("No %s translations to export\n", app)
+		return nil
+	}
+
+	if err := os.MkdirAll(baseDirectory, 0755); err != nil {
+		return err
+	}
+
+	written := make(map[string]bool)
+	sourcePath := filepath.Join(baseDirectory, prefix+"_en.arb")
+
+	for _, lang := range tm.Languages {
+		values := make(map[string]interface{})
+		processedPlurals := make(map[string]bool)
+		containsValue := false
+
+		for _, t := range translations {
+			v := t.Values[lang]
+			if v == "" {
+				continue
+			}
+			containsValue = true
+
+			key := t.Key
+			if t.IsPlural() {
+				key = t.GetSingularKey()
+				if processedPlurals[key] {
+					continue
+				}
+				processedPlurals[key] = true
+				if pluralValues := tm.GetPlural(app, key); pluralValues != nil {
+					one, other := pluralValues.One[lang], pluralValues.Other[lang]
+					if other == "" {
+						other = one
+					}
+					v = NativePluralToICU(arbPluralArgument(t.Placeholders), lang, one, other, true)
+				}
+			}
+			values[key] = v
+
+			if lang == "en" && (t.Comment != "" || t.Placeholders != "") {
+				meta := arbMetadata{Description: t.Comment}
+				if t.Placeholders != "" {
+					meta.Placeholders = json.RawMessage(t.Placeholders)
+				}
+				values["@"+key] = meta
+			}
+		}
+
+		if !containsValue {
+			continue
+		}
+
+		targetPath := filepath.Join(baseDirectory, prefix+"_"+lang+".arb")
+
+		// Metadata always follows its key, whatever the order of the keys is
+		keys := make([]string, 0, len(values))
+		for k := range values {
+			if !strings.HasPrefix(k, "@") {
+				keys = append(keys, k)
+			}
+		}
+		keys = orderKeys(keys, keyOrderFor(opts.KeyOrder, targetPath, sourcePath))
+
+		object := newOrderedObject()
+		object.Set("@@locale", lang)
+		for _, k := range keys {
+			object.Set(k, values[k])
+			if meta, ok := values["@"+k]; ok {
+				object.Set("@"+k, meta)
+			}
+		}
+
+		changed, err := writeJSONFile(targetPath, object)
+		if err != nil {
+			return err
+		}
+		written[targetPath] = true
+		if changed {
+			fmt.Println("Create translation: " + targetPath)
+		}
+	}
+
+	err := removeStalePseudoLocales(tm, func(lang string) []string {
+		return []string{filepath.Join(baseDirectory, prefix+"_"+lang+".arb")}
+	})
+	if err != nil {
+		return err
+	}
+	if err := handleOrphanedFiles(baseDirectory, localeFilePattern(prefix+"_", ".arb"), written, opts.RemoveOrphans); err != nil {
+		return err
+	}
+	fmt.Printf("Exported %s translations to ARB files in %s\n", app, baseDirectory)
+	return nil
+}
+
+// compactJSON returns the compact form of a raw JSON value, empty for null or invalid values
+func compactJSON(raw json.RawMessage) string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, raw); err != nil {
+		return ""
+	}
+	return buf.String()
+}
+
+// arbPluralArgument returns the numeric placeholder of the metadata, "count" if there is none
+func arbPluralArgument(placeholders string) string {
+	var parsed map[string]struct {
+		Type string `json:"type"`
+	}
+	if placeholders == "" || json.Unmarshal([]byte(placeholders), &parsed) != nil {
+		return "count"
+	}
+	names := make([]string, 0, len(parsed))
+	for name := range parsed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		switch parsed[name].Type {
+		case "int", "num", "double":
+			return name
+		}
+	}
+	return "count"
+}
@@ -7,8 +7,12 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"path"
+	"slices"
+	"sort"
 	"strings"
 	"time"
+	"unicode/utf8"
 )
 
 // TranslationService interface for different translation providers
@@ -19,7 +23,9 @@ type TranslationService interface {
 
 // DeepLTranslator implements TranslationService for DeepL API
 type DeepLTranslator struct {
-	APIKey string
+	APIKey   string
+	Endpoint string
+	Client   *http.Client // optional, e.g. with a recording transport
 }
 
 func (d *DeepLTranslator) Name() string {
@@ -31,7 +37,10 @@ func (d *DeepLTranslator) Translate(text, sourceLang, targetLang string) (string
 		return "", fmt.Errorf("DeepL API key not set. Set DEEPL_API_KEY environment variable")
 	}
 
-	url := "https://api-free.deepl.com/v2/translate"
+	url := d.Endpoint
+	if url == "" {
+		url = "https://api-free.deepl.com/v2/translate"
+	}
 
 	requestBody, err := json.Marshal(map[string]interface{}{
 		"text":        []string{text},
@@ -50,8 +59,7 @@ func (d *DeepLTranslator) Translate(text, sourceLang, targetLang string) (string
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "DeepL-Auth-Key "+d.APIKey)
 
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Do(req)
+	resp, err := httpClient(d.Client, 10*time.Second).Do(req)
 	if err != nil {
 		return "", fmt.Errorf("error sending request: %v", err)
 	}
@@ -87,6 +95,7 @@ type AzureTranslator struct {
 	Key      string
 	Region   string
 	Endpoint string
+	Client   *http.Client // optional, e.g. with a recording transport
 }
 
 func (a *AzureTranslator) Name() string {
@@ -124,8 +133,7 @@ func (a *AzureTranslator) Translate(text, sourceLang, targetLang string) (string
 	req.Header.Set("Ocp-Apim-Subscription-Key", a.Key)
 	req.Header.Set("Ocp-Apim-Subscription-Region", a.Region)
 
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Do(req)
+	resp, err := httpClient(a.Client, 10*time.Second).Do(req)
 	if err != nil {
 		return "", fmt.Errorf("error sending request: %v", err)
 	}
@@ -158,11 +166,108 @@ func (a *AzureTranslator) Translate(text, sourceLang, targetLang string) (string
 	return result[0].Translations[0].Text, nil
 }
 
+// httpClient returns the configured client or a default client with the given timeout
+func httpClient(client *http.Client, timeout time.Duration) *http.Client {
+	if client != nil {
+		return client
+	}
+	return &http.Client{Timeout: timeout}
+}
+
+// isReplayClient reports whether a client serves recorded responses instead of calling the API
+func isReplayClient(client *http.Client) bool {
+	if client == nil {
+		return false
+	}
+	rt, ok := client.Transport.(*RecordingTransport)
+	return ok && rt.Mode == ReplayMode
+}
+
+// rateLimited reports whether the requests of a service go to a real provider with a rate limit
+func rateLimited(service TranslationService) bool {
+	switch s := service.(type) {
+	case *DeepLTranslator:
+		return !isReplayClient(s.Client)
+	case *AzureTranslator:
+		return !isReplayClient(s.Client)
+	}
+	return false
+}
+
+// throttle keeps to the rate limit of the real providers: it pauses after every 70 requests
+func throttle(service TranslationService, requestCount int) {
+	if rateLimited(service) && requestCount > 0 && requestCount%70 == 0 {
+		fmt.Println("Rate limit reached, sleeping for 60 seconds...")
+		time.Sleep(60 * time.Second)
+	}
+}
+
 // GetTranslationService returns an appropriate translation service based on environment variables
 func GetTranslationService() TranslationService {
 	// Check for Azure API key first
-	azureKey := os.Getenv("AZURE_TRANSLATOR_KEY")
-	azureRegion := os.Getenv("AZURE_TRANSLATOR_REGION")
+	if azure := newAzureTranslator(nil); azure.Key != "" && azure.Region != "" {
+		fmt.Println("Using Azure Translator service")
+		return azure
+	}
+
+	// Fall back to DeepL
+	if deepl := newDeepLTranslator(nil); deepl.APIKey != "" {
+		fmt.Println("Using DeepL translation service")
+		return deepl
+	}
+
+	// No translation service available
+	return nil
+}
+
+// NewTranslationService creates the named service (auto|deepl|azure|fake). The client is
+// optional and allows to record, replay or redirect the HTTP traffic of the service.
+func NewTranslationService(name string, client *http.Client) (TranslationService, error) {
+	replaying := isReplayClient(client)
+
+	switch name {
+	case "auto", "":
+		service := GetTranslationService()
+		switch s := service.(type) {
+		case *AzureTranslator:
+			s.Client = client
+		case *DeepLTranslator:
+			s.Client = client
+		}
+		return service, nil
+	case "deepl":
+		deepl := newDeepLTranslator(client)
+		if deepl.APIKey == "" && replaying {
+			deepl.APIKey = "replay"
+		}
+		return deepl, nil
+	case "azure":
+		azure := newAzureTranslator(client)
+		if replaying {
+			if azure.Key == "" {
+				azure.Key = "replay"
+			}
+			if azure.Region == "" {
+				azure.Region = "replay"
+			}
+		}
+		return azure, nil
+	case "fake":
+		return NewFakeTranslator(os.Getenv("FAKE_TRANSLATOR_DICTIONARY"))
+	default:
+		return nil, fmt.Errorf("unknown service: %s. Use 'deepl', 'azure', 'fake' or 'auto'", name)
+	}
+}
+
+func newDeepLTranslator(client *http.Client) *DeepLTranslator {
+	return &DeepLTranslator{
+		APIKey:   os.Getenv("DEEPL_API_KEY"),
+		Endpoint: os.Getenv("DEEPL_ENDPOINT"),
+		Client:   client,
+	}
+}
+
+func newAzureTranslator(client *http.Client) *AzureTranslator {
 	azureEndpoint := os.Getenv("AZURE_TRANSLATOR_ENDPOINT")
 
 	// Default endpoint if not specified
@@ -170,45 +275,151 @@ func GetTranslationService() TranslationService {
 		azureEndpoint = "https://api.cognitive.microsofttranslator.com/translate"
 	}
 
-	// If Azure credentials are available, use Azure
-	if azureKey != "" && azureRegion != "" {
-		fmt.Println("Using Azure Translator service")
-		return &AzureTranslator{
-			Key:      azureKey,
-			Region:   azureRegion,
-			Endpoint: azureEndpoint,
+	return &AzureTranslator{
+		Key:      os.Getenv("AZURE_TRANSLATOR_KEY"),
+		Region:   os.Getenv("AZURE_TRANSLATOR_REGION"),
+		Endpoint: azureEndpoint,
+		Client:   client,
+	}
+}
+
+// providerCostPerMillion holds the list price in EUR per million characters,
+// used for the dry-run cost estimate
+var providerCostPerMillion = map[string]float64{
+	"DeepL":            20,
+	"Azure Translator": 10,
+}
+
+// AutoTranslateOptions limits which cells auto-translate touches
+type AutoTranslateOptions struct {
+	Apps         []string // only these apps, all if empty
+	KeyPatterns  []string // only keys matching one of these globs, all if empty
+	Languages    []string // only these target languages, all non-regional if empty
+	IncludeStale bool     // also retranslate machine translations whose English source changed
+	DryRun       bool     // only print what would be sent and the estimated cost
+}
+
+// translationJob is a single cell that needs a translation
+type translationJob struct {
+	Row        int
+	TargetLang string
+	Source     string
+	Stale      bool
+}
+
+func (o AutoTranslateOptions) matchesRow(row TranslationRow) bool {
+	if len(o.Apps) > 0 && !slices.Contains(o.Apps, row.App) {
+		return false
+	}
+	if len(o.KeyPatterns) == 0 {
+		return true
+	}
+	for _, pattern := range o.KeyPatterns {
+		if ok, _ := path.Match(pattern, row.Key); ok {
+			return true
 		}
 	}
+	return false
+}
 
-	// Fall back to DeepL
-	deeplKey := os.Getenv("DEEPL_API_KEY")
-	if deeplKey != "" {
-		fmt.Println("Using DeepL translation service")
-		return &DeepLTranslator{APIKey: deeplKey}
+// isStale reports whether a cell was machine translated from an English text that has since changed
+func isStale(memory *TranslationMemory, row TranslationRow, lang, source string) bool {
+	if memory == nil {
+		return false
 	}
+	entry := memory.CellEntry(row.App, row.Key, lang, row.Values[lang])
+	return entry != nil && entry.Provider != ProviderCSV && entry.Source != source
+}
 
-	// No translation service available
-	return nil
+// planAutoTranslate collects all cells that need a translation according to the options
+func planAutoTranslate(tm *Translations, memory *TranslationMemory, sourceLang string, targetLanguages []string, opts AutoTranslateOptions) []translationJob {
+	jobs := make([]translationJob, 0)
+	for i, row := range tm.Translations {
+		source := row.Values[sourceLang]
+		if source == "" || !opts.matchesRow(row) {
+			continue
+		}
+		for _, lang := range targetLanguages {
+			if row.Values[lang] == "" {
+				jobs = append(jobs, translationJob{Row: i, TargetLang: lang, Source: source})
+			} else if opts.IncludeStale && isStale(memory, row, lang, source) {
+				jobs = append(jobs, translationJob{Row: i, TargetLang: lang, Source: source, Stale: true})
+			}
+		}
+	}
+	return jobs
 }
 
-// AutoTranslateFromEnglish performs automatic translation from English only
-func AutoTranslateFromEnglish(tm *Translations, service TranslationService) (int, error) {
-	if service == nil {
+// printDryRun prints strings and characters per language and the estimated cost per provider.
+// Like the real run, a source text is only sent once per language and reused afterwards.
+func printDryRun(jobs []translationJob, memory *TranslationMemory, sourceLang string, targetLanguages []string) {
+	strs := make(map[string]int)
+	chars := make(map[string]int)
+	reused := 0
+	sent := make(map[string]bool)
+	for _, job := range jobs {
+		if memory != nil && !job.Stale && (sent[job.TargetLang+"\x00"+job.Source] || memory.Lookup(job.Source, sourceLang, job.TargetLang) != nil) {
+			reused++
+			continue
+		}
+		sent[job.TargetLang+"\x00"+job.Source] = true
+		strs[job.TargetLang]++
+		chars[job.TargetLang] += utf8.RuneCountInString(job.Source)
+	}
+
+	providers := make([]string, 0, len(providerCostPerMillion))
+	for name := range providerCostPerMillion {
+		providers = append(providers, name)
+	}
+	sort.Strings(providers)
+
+	fmt.Printf("Dry run, nothing is sent. %d strings can be reused from memory.\n\n", reused)
+	fmt.Printf("%-10s %8s %10s", "language", "strings", "chars")
+	for _, name := range providers {
+		fmt.Printf(" %18s", name)
+	}
+	fmt.Println()
+
+	totalStrings, totalChars := 0, 0
+	for _, lang := range targetLanguages {
+		fmt.Printf("%-10s %8d %10d", lang, strs[lang], chars[lang])
+		for _, name := range providers {
+			fmt.Printf(" %16.2f €", float64(chars[lang])*providerCostPerMillion[name]/1_000_000)
+		}
+		fmt.Println()
+		totalStrings += strs[lang]
+		totalChars += chars[lang]
+	}
+	fmt.Printf("%-10s %8d %10d", "total", totalStrings, totalChars)
+	for _, name := range providers {
+		fmt.Printf(" %16.2f €", float64(totalChars)*providerCostPerMillion[name]/1_000_000)
+	}
+	fmt.Println()
+}
+
+// AutoTranslateFromEnglish performs automatic translation from English only.
+// Exact matches from the translation memory are reused before the service is called.
+func AutoTranslateFromEnglish(tm *Translations, service TranslationService, memory *TranslationMemory, opts AutoTranslateOptions) (int, error) {
+	if service == nil && !opts.DryRun {
 		return 0, fmt.Errorf("no translation service configured")
 	}
 
 	sourceLang := "en"
 	translatedCount := 0
 	requestCount := 0
-
-	fmt.Printf("Using %s for translation from English to all other languages\n", service.Name())
+	reusedCount := 0
+	tooLongCount := 0
 
 	// Get all target languages (exclude English and regional variants)
 	targetLanguages := []string{}
 	for _, lang := range tm.Languages {
-		if lang != sourceLang && !strings.Contains(lang, "_") && !strings.Contains(lang, "-") {
-			targetLanguages = append(targetLanguages, lang)
+		if lang == sourceLang || isRegion(lang) {
+			continue
+		}
+		if len(opts.Languages) > 0 && !slices.Contains(opts.Languages, lang) {
+			continue
 		}
+		targetLanguages = append(targetLanguages, lang)
 	}
 
 	if len(targetLanguages) == 0 {
@@ -217,50 +428,88 @@ func AutoTranslateFromEnglish(tm *Translations, service TranslationService) (int
 
 	fmt.Printf("Target languages: %s\n", strings.Join(targetLanguages, ", "))
 
-	// Debug: Count how many strings have English content
-	englishStrings := 0
-	for _, row := range tm.Translations {
-		if sourceValues, hasEnglish := row.Values[sourceLang]; hasEnglish && sourceValues != "" {
-			englishStrings++
-		}
+	jobs := planAutoTranslate(tm, memory, sourceLang, targetLanguages, opts)
+	fmt.Printf("Found %d strings to translate\n", len(jobs))
+
+	if opts.DryRun {
+		printDryRun(jobs, memory, sourceLang, targetLanguages)
+		return 0, nil
 	}
-	fmt.Printf("Found %d strings with English content\n", englishStrings)
 
-	for i, row := range tm.Translations {
-		// Check if we have English source content
-		sourceValues, hasEnglish := row.Values[sourceLang]
-		if !hasEnglish || sourceValues == "" {
-			continue // Skip if no English content
-		}
+	fmt.Printf("Using %s for translation from English\n", service.Name())
 
-		for _, targetLang := range targetLanguages {
-			// Rate limit: pause after every 70 requests
+	for _, job := range jobs {
+		row := tm.Translations[job.Row]
+		if tm.Translations[job.Row].Values == nil {
+			tm.Translations[job.Row].Values = make(map[string]string)
+		}
 
-			// Check current target translation - get directly from Values, not with fallback
-			targetValues, _ := row.Values[targetLang]
+		// The services cannot be told about a length limit, longer translations are not saved
+		limit, limited := rowLengthLimit(row)
+		tooLong := func(text string) bool {
+			if !limited || !limit.Exceeds(text) {
+				return false
+			}
+			fmt.Printf("Warning: %s:%s [%s] %q is %d long, the limit is %s, not saved, translate it manually\n", row.App, row.Key, job.TargetLang, text, limit.Measure(text), limit)
+			tooLongCount++
+			return true
+		}
 
-			if targetValues == "" {
-				if requestCount > 0 && requestCount%70 == 0 {
-					fmt.Println("Rate limit reached, sleeping for 60 seconds...")
-					time.Sleep(60 * time.Second)
+		if memory != nil && !job.Stale {
+			if hit := memory.Lookup(job.Source, sourceLang, job.TargetLang); hit != nil {
+				if tooLong(hit.Target) {
+					continue
 				}
+				tm.Translations[job.Row].Values[job.TargetLang] = hit.Target
+				memory.Record(MemoryEntry{
+					SourceLang: sourceLang,
+					TargetLang: job.TargetLang,
+					Source:     job.Source,
+					Target:     hit.Target,
+					Provider:   hit.Provider,
+					App:        row.App,
+					Key:        row.Key,
+					Reused:     true,
+				})
+				translatedCount++
+				reusedCount++
+				fmt.Printf("Reusing [en→%s]: %s -> %s (%s)\n", job.TargetLang, job.Source, hit.Target, hit.Provider)
+				continue
+			}
+		}
 
-				translatedText, err := service.Translate(sourceValues, sourceLang, targetLang)
-				requestCount++
-
-				if err != nil {
-					fmt.Printf("Error translating to %s: %v\n", targetLang, err)
-				} else {
-					if tm.Translations[i].Values == nil {
-						tm.Translations[i].Values = make(map[string]string)
-					}
-					tm.Translations[i].Values[targetLang] = translatedText
-					translatedCount++
-				}
-				fmt.Printf("Translating [en→%s]: %s -> %s\n", targetLang, sourceValues, translatedText)
+		throttle(service, requestCount)
 
-			}
+		translatedText, err := service.Translate(job.Source, sourceLang, job.TargetLang)
+		requestCount++
+
+		if err != nil {
+			fmt.Printf("Error translating to %s: %v\n", job.TargetLang, err)
+			continue
+		}
+
+		// Recorded in the memory even if too long, so it is not requested again
+		if memory != nil {
+			memory.Record(MemoryEntry{
+				SourceLang: sourceLang,
+				TargetLang: job.TargetLang,
+				Source:     job.Source,
+				Target:     translatedText,
+				Provider:   service.Name(),
+				App:        row.App,
+				Key:        row.Key,
+			})
 		}
+		if tooLong(translatedText) {
+			continue
+		}
+		tm.Translations[job.Row].Values[job.TargetLang] = translatedText
+		translatedCount++
+		fmt.Printf("Translating [en→%s]: %s -> %s\n", job.TargetLang, job.Source, translatedText)
+	}
+	fmt.Printf("Sent %d requests to %s, reused %d translations from memory\n", requestCount, service.Name(), reusedCount)
+	if tooLongCount > 0 {
+		fmt.Printf("%d translations exceed their length limit and need a shorter manual translation\n", tooLongCount)
 	}
 	return translatedCount, nil
 }
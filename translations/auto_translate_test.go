@@ -0,0 +1,207 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newDeepLServer returns a server that answers like DeepL from a dictionary and counts requests
+func newDeepLServer(t *testing.T, translations map[string]string, requests *int) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		if r.Method != http.MethodPost || r.URL.Path != "/v2/translate" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "DeepL-Auth-Key test-key" {
+			t.Errorf("Authorization = %q", got)
+		}
+
+		var request struct {
+			Text       []string `json:"text"`
+			SourceLang string   `json:"source_lang"`
+			TargetLang string   `json:"target_lang"`
+		}
+		// The handler runs in the server's goroutine, so it reports with Errorf and returns
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Errorf("invalid request body: %v", err)
+			http.Error(w, `{"message":"invalid request body"}`, http.StatusBadRequest)
+			return
+		}
+		if request.SourceLang != "EN" || len(request.Text) != 1 {
+			t.Errorf("unexpected request %+v", request)
+			http.Error(w, `{"message":"unexpected request"}`, http.StatusBadRequest)
+			return
+		}
+
+		translated, ok := translations[request.TargetLang+":"+request.Text[0]]
+		if !ok {
+			http.Error(w, `{"message":"unknown text"}`, http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"translations": []map[string]string{{"detected_source_language": "EN", "text": translated}},
+		})
+	}))
+}
+
+func TestDeepLTranslator(t *testing.T) {
+	requests := 0
+	server := newDeepLServer(t, map[string]string{"DE:Delete": "Löschen"}, &requests)
+	defer server.Close()
+
+	deepl := &DeepLTranslator{APIKey: "test-key", Endpoint: server.URL + "/v2/translate"}
+	got, err := deepl.Translate("Delete", "en", "de")
+	if err != nil {
+		t.Fatalf("Translate: %v", err)
+	}
+	if got != "Löschen" {
+		t.Errorf("Translate = %q, want %q", got, "Löschen")
+	}
+
+	if _, err := deepl.Translate("Unknown", "en", "de"); err == nil || !strings.Contains(err.Error(), "status 400") {
+		t.Errorf("Translate of an unknown text: err = %v, want status 400", err)
+	}
+	if requests != 2 {
+		t.Errorf("requests = %d, want 2", requests)
+	}
+}
+
+func TestAzureTranslator(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("api-version") != "3.0" || query.Get("from") != "en" || query.Get("to") != "de" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		if r.Header.Get("Ocp-Apim-Subscription-Key") != "test-key" || r.Header.Get("Ocp-Apim-Subscription-Region") != "westeurope" {
+			t.Errorf("missing credentials in %v", r.Header)
+		}
+
+		var request []map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil || len(request) != 1 || request[0]["Text"] != "Delete" {
+			t.Errorf("unexpected request body %v: %v", request, err)
+			http.Error(w, `{"error":{"message":"unexpected request body"}}`, http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"translations":[{"text":"Löschen","to":"de"}]}]`))
+	}))
+	defer server.Close()
+
+	azure := &AzureTranslator{Key: "test-key", Region: "westeurope", Endpoint: server.URL + "/translate"}
+	got, err := azure.Translate("Delete", "en", "de")
+	if err != nil {
+		t.Fatalf("Translate: %v", err)
+	}
+	if got != "Löschen" {
+		t.Errorf("Translate = %q, want %q", got, "Löschen")
+	}
+}
+
+func TestAutoTranslateFromEnglishReusesMemory(t *testing.T) {
+	requests := 0
+	server := newDeepLServer(t, map[string]string{"DE:Delete": "Löschen", "DE:Cancel": "Abbrechen"}, &requests)
+	defer server.Close()
+
+	tm := NewTranslations("")
+	tm.Languages = []string{"de", "de_AT", "en"}
+	tm.Translations = []TranslationRow{
+		{App: "android", Key: "delete", Values: map[string]string{"en": "Delete"}},
+		{App: "ios", Key: "cancel", Values: map[string]string{"en": "Cancel", "de": "Abbrechen"}},
+		{App: "web", Key: "cancel", Values: map[string]string{"en": "Cancel"}},
+		{App: "web", Key: "delete", Values: map[string]string{"en": "Delete"}},
+	}
+	memory, err := LoadTranslationMemory(filepath.Join(t.TempDir(), "memory.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	memory.Harvest(tm, "en")
+
+	deepl := &DeepLTranslator{APIKey: "test-key", Endpoint: server.URL + "/v2/translate"}
+	count, err := AutoTranslateFromEnglish(tm, deepl, memory, AutoTranslateOptions{})
+	if err != nil {
+		t.Fatalf("AutoTranslateFromEnglish: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("translated %d cells, want 3", count)
+	}
+	// "Delete" is sent once and reused, "Cancel" comes from the harvested CSV value
+	if requests != 1 {
+		t.Errorf("requests = %d, want 1", requests)
+	}
+	for _, row := range tm.Translations {
+		if row.Values["de"] == "" {
+			t.Errorf("%s:%s has no German value", row.App, row.Key)
+		}
+		if row.Values["de_AT"] != "" {
+			t.Errorf("%s:%s: regional language de_AT was translated", row.App, row.Key)
+		}
+	}
+}
+
+func TestAutoTranslateFromEnglishLengthLimit(t *testing.T) {
+	requests := 0
+	server := newDeepLServer(t, map[string]string{"DE:Delete": "Löschen"}, &requests)
+	defer server.Close()
+
+	tm := NewTranslations("")
+	tm.Languages = []string{"de", "en"}
+	tm.Translations = []TranslationRow{
+		{App: "android", Key: "delete", MaxLength: "6", Values: map[string]string{"en": "Delete"}},
+		{App: "web", Key: "delete", MaxLength: "5", Values: map[string]string{"en": "Delete"}},
+		{App: "ios", Key: "delete", Values: map[string]string{"en": "Delete"}},
+	}
+	memory, err := LoadTranslationMemory(filepath.Join(t.TempDir(), "memory.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deepl := &DeepLTranslator{APIKey: "test-key", Endpoint: server.URL + "/v2/translate"}
+	count, err := AutoTranslateFromEnglish(tm, deepl, memory, AutoTranslateOptions{})
+	if err != nil {
+		t.Fatalf("AutoTranslateFromEnglish: %v", err)
+	}
+	if count != 1 || requests != 1 {
+		t.Errorf("translated %d cells with %d requests, want 1 and 1", count, requests)
+	}
+	// The too long translation is kept in the memory but not saved to rows with a limit
+	for _, row := range tm.Translations {
+		want := ""
+		if row.MaxLength == "" {
+			want = "Löschen"
+		}
+		if got := row.Values["de"]; got != want {
+			t.Errorf("%s:%s [de] = %q, want %q", row.App, row.Key, got, want)
+		}
+	}
+	if hit := memory.Lookup("Delete", "en", "de"); hit == nil || hit.Target != "Löschen" {
+		t.Errorf("memory lookup = %+v, want Löschen", hit)
+	}
+}
+
+func TestRateLimited(t *testing.T) {
+	replay, err := NewRecordingClient(ReplayMode, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		service TranslationService
+		want    bool
+	}{
+		{&DeepLTranslator{}, true},
+		{&AzureTranslator{}, true},
+		{&DeepLTranslator{Client: replay}, false},
+		{&AzureTranslator{Client: replay}, false},
+		{&FakeTranslator{}, false},
+	}
+	for _, tt := range tests {
+		if got := rateLimited(tt.service); got != tt.want {
+			t.Errorf("rateLimited(%T) = %v, want %v", tt.service, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Parameter types derived from placeholders
+const (
+	paramString = "string"
+	paramInt    = "int"
+	paramFloat  = "float"
+)
+
+// codegenParam is a parameter of a generated accessor
+type codegenParam struct {
+	Name     string // argN for printf placeholders, the placeholder name otherwise
+	Type     string
+	Named    bool // {name} or ${name} placeholder
+	Position int  // 1-based position of printf placeholders
+}
+
+// codegenKey is a key of an app as seen by the code generators. Plural pairs are a single
+// key with the singular and plural row keys.
+type codegenKey struct {
+	Key         string
+	Source      string
+	Comment     string
+	Params      []codegenParam
+	Plural      bool
+	SingularKey string
+	PluralKey   string
+	One         string
+	Other       string
+}
+
+// collectCodegenKeys returns the keys of an app that have an English source, sorted by key
+func collectCodegenKeys(tm *Translations, app string) []codegenKey {
+	tm.Sort()
+
+	result := make([]codegenKey, 0)
+	processedPlurals := make(map[string]bool)
+	for _, row := range tm.GetTranslationsForApp(app) {
+		source := row.Values["en"]
+		if !row.IsPlural() {
+			if source == "" {
+				continue
+			}
+			result = append(result, codegenKey{Key: row.Key, Source: source, Comment: row.Comment, Params: placeholderParams(source, false)})
+			continue
+		}
+
+		key := row.GetSingularKey()
+		if processedPlurals[key] {
+			continue
+		}
+		processedPlurals[key] = true
+
+		plural := tm.GetPlural(app, key)
+		if plural == nil || (plural.One["en"] == "" && plural.Other["en"] == "") {
+			continue
+		}
+		one, other := plural.One["en"], plural.Other["en"]
+		if other == "" {
+			other = one
+		}
+		result = append(result, codegenKey{
+			Key:         key,
+			Source:      other,
+			Comment:     row.Comment,
+			Params:      placeholderParams(other, true),
+			Plural:      true,
+			SingularKey: key + ".singular",
+			PluralKey:   key + ".plural",
+			One:         one,
+			Other:       other,
+		})
+	}
+	return result
+}
+
+// placeholderParams derives the parameters of a value from its placeholders: printf
+// placeholders by position, followed by the named ones in order of appearance. For plurals
+// a leading numeric placeholder is the count and not returned.
+func placeholderParams(value string, plural bool) []codegenParam {
+	printf := make(map[int]string)
+	named := make([]codegenParam, 0)
+	seen := make(map[string]bool)
+	position := 0
+
+	for _, placeholder := range placeholderPattern.FindAllString(value, -1) {
+		if !strings.HasPrefix(placeholder, "%") {
+			name := strings.Trim(placeholder, "${}")
+			if !seen[name] {
+				seen[name] = true
+				named = append(named, codegenParam{Name: name, Type: paramString, Named: true})
+			}
+			continue
+		}
+
+		position++
+		index := position
+		if i := strings.Index(placeholder, "$"); i > 0 {
+			if n, err := strconv.Atoi(placeholder[1:i]); err == nil {
+				index = n
+			}
+		}
+		printf[index] = printfParamType(placeholder)
+	}
+
+	result := make([]codegenParam, 0, len(printf)+len(named))
+	maxIndex := 0
+	for index := range printf {
+		maxIndex = max(maxIndex, index)
+	}
+	for index := 1; index <= maxIndex; index++ {
+		paramType, ok := printf[index]
+		if !ok {
+			paramType = paramString
+		}
+		if plural && index == 1 && paramType == paramInt {
+			continue
+		}
+		result = append(result, codegenParam{Name: fmt.Sprintf("arg%d", index), Type: paramType, Position: index})
+	}
+	return append(result, named...)
+}
+
+// printfParamType returns the parameter type of a printf placeholder by its verb
+func printfParamType(placeholder string) string {
+	switch placeholder[len(placeholder)-1] {
+	case 'd', 'i', 'u', 'x', 'X', 'c', 'b':
+		return paramInt
+	case 'f':
+		return paramFloat
+	default:
+		return paramString
+	}
+}
+
+// hasCountPlaceholder reports whether a plural form starts with a numeric printf placeholder
+func hasCountPlaceholder(form string) bool {
+	for _, placeholder := range placeholderPattern.FindAllString(form, -1) {
+		if strings.HasPrefix(placeholder, "%") {
+			return printfParamType(placeholder) == paramInt
+		}
+	}
+	return false
+}
+
+// countPrintfPlaceholders returns the number of printf arguments a value consumes
+func countPrintfPlaceholders(value string) int {
+	count := 0
+	for _, placeholder := range placeholderPattern.FindAllString(value, -1) {
+		if strings.HasPrefix(placeholder, "%") {
+			count++
+		}
+	}
+	return count
+}
+
+// identifierWords splits a key into words at separators and lower/upper case boundaries
+func identifierWords(key string) []string {
+	words := make([]string, 0)
+	var current []rune
+	var previous rune
+	for _, r := range key {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if len(current) > 0 {
+				words = append(words, string(current))
+				current = nil
+			}
+			previous = 0
+			continue
+		}
+		if unicode.IsUpper(r) && len(current) > 0 && (unicode.IsLower(previous) || unicode.IsDigit(previous)) {
+			words = append(words, string(current))
+			current = nil
+		}
+		current = append(current, r)
+		previous = r
+	}
+	if len(current) > 0 {
+		words = append(words, string(current))
+	}
+	return words
+}
+
+// pascalCase converts a key to an exported identifier, e.g. account_cancelled.subject →
+// AccountCancelledSubject. Identifiers that would start with a digit are prefixed with "N".
+func pascalCase(key string) string {
+	var sb strings.Builder
+	for _, word := range identifierWords(key) {
+		runes := []rune(word)
+		sb.WriteRune(unicode.ToUpper(runes[0]))
+		sb.WriteString(string(runes[1:]))
+	}
+	result := sb.String()
+	if result == "" || unicode.IsDigit([]rune(result)[0]) {
+		result = "N" + result
+	}
+	return result
+}
+
+// camelCase converts a key to an unexported identifier, e.g. account_cancelled → accountCancelled
+func camelCase(key string) string {
+	result := []rune(pascalCase(key))
+	result[0] = unicode.ToLower(result[0])
+	return string(result)
+}
+
+// uniqueIdentifiers maps keys to identifiers, keys whose identifier is already taken are
+// reported and left out
+func uniqueIdentifiers(app string, keys []string, identifier func(string) string, reserved ...string) map[string]string {
+	taken := make(map[string]string)
+	for _, name := range reserved {
+		taken[name] = ""
+	}
+
+	result := make(map[string]string, len(keys))
+	for _, key := range keys {
+		name := identifier(key)
+		if other, ok := taken[name]; ok {
+			if other == "" {
+				fmt.Printf("Warning: %s:%s: identifier %s is reserved, skipping\n", app, key, name)
+			} else {
+				fmt.Printf("Warning: %s:%s: identifier %s is already used by %s, skipping\n", app, key, name, other)
+			}
+			continue
+		}
+		taken[name] = key
+		result[key] = name
+	}
+	return result
+}
+
+// writeGeneratedFile writes generated code, the file is left untouched if its content would
+// not change
+func writeGeneratedFile(targetPath string, content []byte) (bool, error) {
+	if existing, err := os.ReadFile(targetPath); err == nil && bytes.Equal(existing, content) {
+		return false, nil
+	}
+	if err := os.WriteFile(targetPath, content, 0644); err != nil {
+		return false, fmt.Errorf("error writing %s: %v", targetPath, err)
+	}
+	return true, nil
+}
+
+// passesCount reports whether the count of a plural key is the first format argument, which
+// is the case unless a non-numeric placeholder takes the first position
+func passesCount(k codegenKey) bool {
+	for _, p := range k.Params {
+		if p.Position == 1 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultGoAccessorFile is the name of the generated Go file in the translation directory
+const DefaultGoAccessorFile = "keys_gen.go"
+
+// GoAccessorOptions configures the generated Go package
+type GoAccessorOptions struct {
+	Package string `json:"go_package,omitempty"` // defaults to the name of the directory
+	Embed   bool   `json:"go_embed"`             // embed the JSON files of the directory as Files
+}
+
+// goParamTypes maps parameter types to Go types
+var goParamTypes = map[string]string{
+	paramString: "string",
+	paramInt:    "int",
+	paramFloat:  "float64",
+}
+
+// GenerateGoAccessors writes a Go file with a Key constant per row and a typed function per
+// key with placeholders or plural forms into the directory of the app's JSON files
+func GenerateGoAccessors(tm *Translations, app, baseDirectory string, opts GoAccessorOptions) error {
+	keys := collectCodegenKeys(tm, app)
+	if len(keys) == 0 {
+		fmt.Printf("No %s keys to generate Go accessors for\n", app)
+		return nil
+	}
+
+	packageName := opts.Package
+	if packageName == "" {
+		packageName = filepath.Base(baseDirectory)
+	}
+
+	rowKeys := make([]string, 0, len(keys))
+	for _, k := range keys {
+		if k.Plural {
+			rowKeys = append(rowKeys, k.SingularKey, k.PluralKey)
+		} else {
+			rowKeys = append(rowKeys, k.Key)
+		}
+	}
+	constants := uniqueIdentifiers(app, rowKeys, func(key string) string { return "Key" + pascalCase(key) })
+
+	functionKeys := make([]string, 0)
+	for _, k := range keys {
+		if k.Plural || len(k.Params) > 0 {
+			functionKeys = append(functionKeys, k.Key)
+		}
+	}
+	functions := uniqueIdentifiers(app, functionKeys, pascalCase, "Key", "Lookup", "Files")
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by the zeitkapsl translations tool from the translations CSV. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", packageName)
+
+	imports := make([]string, 0)
+	if opts.Embed {
+		imports = append(imports, "embed")
+	}
+	needsFmt, needsStrings := false, false
+	for _, k := range keys {
+		if _, ok := functions[k.Key]; !ok {
+			continue
+		}
+		if countPrintfPlaceholders(k.Source) > 0 || countPrintfPlaceholders(k.One) > 0 {
+			needsFmt = true
+		}
+		for _, p := range k.Params {
+			needsStrings = needsStrings || p.Named
+		}
+	}
+	if needsFmt {
+		imports = append(imports, "fmt")
+	}
+	if needsStrings {
+		imports = append(imports, "strings")
+	}
+	if len(imports) > 0 {
+		buf.WriteString("import (\n")
+		for _, imp := range imports {
+			fmt.Fprintf(&buf, "\t%q\n", imp)
+		}
+		buf.WriteString(")\n\n")
+	}
+
+	if opts.Embed {
+		buf.WriteString("// Files contains the translation files of all languages\n//\n//go:embed *.json\nvar Files embed.FS\n\n")
+	}
+
+	buf.WriteString("// Key is a translation key\ntype Key string\n\n")
+	buf.WriteString("// Lookup returns the translation of a key in the current language\ntype Lookup func(key Key) string\n\n")
+
+	buf.WriteString("// Translation keys\nconst (\n")
+	for _, key := range rowKeys {
+		if name, ok := constants[key]; ok {
+			fmt.Fprintf(&buf, "\t%s Key = %q\n", name, key)
+		}
+	}
+	buf.WriteString(")\n")
+
+	for _, k := range keys {
+		name, ok := functions[k.Key]
+		if !ok {
+			continue
+		}
+		if k.Plural {
+			singular, okSingular := constants[k.SingularKey]
+			plural, okPlural := constants[k.PluralKey]
+			if !okSingular || !okPlural {
+				continue
+			}
+			fmt.Fprintf(&buf, "\n// %s returns %s, or its singular form for a count of 1\n", name, goDocQuote(k.Other))
+			fmt.Fprintf(&buf, "func %s(%s) string {\n", name, goParams(k.Params, true))
+			fmt.Fprintf(&buf, "\tif count == 1 {\n\t\treturn %s\n\t}\n", goFormatExpression(singular, k.One, k.Params, true))
+			fmt.Fprintf(&buf, "\treturn %s\n}\n", goFormatExpression(plural, k.Other, k.Params, true))
+			continue
+		}
+
+		constant, ok := constants[k.Key]
+		if !ok {
+			continue
+		}
+		fmt.Fprintf(&buf, "\n// %s returns %s\n", name, goDocQuote(k.Source))
+		fmt.Fprintf(&buf, "func %s(%s) string {\n", name, goParams(k.Params, false))
+		fmt.Fprintf(&buf, "\treturn %s\n}\n", goFormatExpression(constant, k.Source, k.Params, false))
+	}
+
+	content, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("error formatting generated %s accessors: %v", app, err)
+	}
+
+	targetPath := filepath.Join(baseDirectory, DefaultGoAccessorFile)
+	changed, err := writeGeneratedFile(targetPath, content)
+	if err != nil {
+		return err
+	}
+	if changed {
+		fmt.Println("Generated Go accessors: " + targetPath)
+	}
+	return nil
+}
+
+// goParamName returns the Go parameter name of a placeholder, avoiding keywords and the
+// names used by the generated functions
+func goParamName(p codegenParam) string {
+	if !p.Named {
+		return p.Name
+	}
+	name := camelCase(p.Name)
+	switch {
+	case token.IsKeyword(name), name == "t", name == "count", name == "fmt", name == "strings", goParamTypes[name] != "":
+		name += "Value"
+	}
+	return name
+}
+
+// goParams returns the parameter list of a generated function
+func goParams(params []codegenParam, plural bool) string {
+	result := []string{"t Lookup"}
+	if plural {
+		result = append(result, "count int")
+	}
+	for _, p := range params {
+		result = append(result, goParamName(p)+" "+goParamTypes[p.Type])
+	}
+	return strings.Join(result, ", ")
+}
+
+// goFormatExpression returns the expression formatting a value: printf arguments are passed
+// to fmt.Sprintf, named placeholders are replaced afterwards
+func goFormatExpression(constant, value string, params []codegenParam, plural bool) string {
+	args := make([]string, 0)
+	available := countPrintfPlaceholders(value)
+	if plural && hasCountPlaceholder(value) {
+		args = append(args, "count")
+		available--
+	}
+	for _, p := range params {
+		if !p.Named && available > 0 {
+			args = append(args, goParamName(p))
+			available--
+		}
+	}
+
+	expression := "t(" + constant + ")"
+	if len(args) > 0 {
+		expression = "fmt.Sprintf(" + expression + ", " + strings.Join(args, ", ") + ")"
+	}
+
+	replacements := make([]string, 0)
+	for _, p := range params {
+		if p.Named {
+			name := goParamName(p)
+			replacements = append(replacements, strconv.Quote("${"+p.Name+"}"), name, strconv.Quote("{"+p.Name+"}"), name)
+		}
+	}
+	if len(replacements) > 0 {
+		expression = "strings.NewReplacer(" + strings.Join(replacements, ", ") + ").Replace(" + expression + ")"
+	}
+	return expression
+}
+
+// goDocQuote quotes a value for a doc comment on a single line
+func goDocQuote(value string) string {
+	value = strings.Join(strings.Fields(value), " ")
+	if utf8.RuneCountInString(value) > 80 {
+		value = string([]rune(value)[:77]) + "..."
+	}
+	return strconv.Quote(value)
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// KotlinOptions configures the generated Kotlin object
+type KotlinOptions struct {
+	Package    string // package of the generated file
+	RClass     string // fully qualified R class of the app, e.g. eu.zeitkapsl.R
+	ObjectName string
+}
+
+// kotlinParamTypes maps parameter types to Kotlin types
+var kotlinParamTypes = map[string]string{
+	paramString: "String",
+	paramInt:    "Int",
+	paramFloat:  "Double",
+}
+
+var kotlinKeywords = []string{
+	"as", "break", "class", "continue", "do", "else", "false", "for", "fun", "if", "in", "interface", "is",
+	"null", "object", "package", "return", "super", "this", "throw", "true", "try", "typealias", "typeof",
+	"val", "var", "when", "while",
+}
+
+// GenerateKotlinStrings writes a Kotlin object with a typed function per string and plural
+// resource, so removed keys become compile errors in the app
+func GenerateKotlinStrings(tm *Translations, app, targetPath string, opts KotlinOptions) error {
+	keys := collectCodegenKeys(tm, app)
+	if len(keys) == 0 {
+		fmt.Printf("No %s keys to generate Kotlin strings for\n", app)
+		return nil
+	}
+
+	keyNames := make([]string, 0, len(keys))
+	for _, k := range keys {
+		keyNames = append(keyNames, k.Key)
+	}
+	functions := uniqueIdentifiers(app, keyNames, camelCase)
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by the zeitkapsl translations tool from the translations CSV. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", opts.Package)
+	buf.WriteString("import android.content.Context\n")
+	fmt.Fprintf(&buf, "import %s\n\n", opts.RClass)
+	fmt.Fprintf(&buf, "object %s {\n", opts.ObjectName)
+
+	for i, k := range keys {
+		name, ok := functions[k.Key]
+		if !ok {
+			continue
+		}
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+
+		params := []string{"context: Context"}
+		if k.Plural {
+			params = append(params, "count: Int")
+		}
+		args := make([]string, 0)
+		replacements := make([]string, 0)
+		for _, p := range k.Params {
+			paramName := kotlinParamName(p)
+			params = append(params, paramName+": "+kotlinParamTypes[p.Type])
+			if p.Named {
+				replacements = append(replacements, fmt.Sprintf(".replace(%s, %s)", strconv.Quote("{"+p.Name+"}"), paramName))
+			} else {
+				args = append(args, paramName)
+			}
+		}
+
+		var call string
+		if k.Plural {
+			if passesCount(k) {
+				args = append([]string{"count"}, args...)
+			}
+			call = fmt.Sprintf("context.resources.getQuantityString(R.plurals.%s, %s)", k.Key, strings.Join(append([]string{"count"}, args...), ", "))
+			fmt.Fprintf(&buf, "    /** %s, or its singular form for a count of 1 */\n", kotlinDocQuote(k.Other))
+		} else {
+			call = fmt.Sprintf("context.getString(%s)", strings.Join(append([]string{"R.string." + k.Key}, args...), ", "))
+			fmt.Fprintf(&buf, "    /** %s */\n", kotlinDocQuote(k.Source))
+		}
+		fmt.Fprintf(&buf, "    fun %s(%s): String =\n        %s%s\n", kotlinIdentifier(name), strings.Join(params, ", "), call, strings.Join(replacements, ""))
+	}
+	buf.WriteString("}\n")
+
+	if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
+		return err
+	}
+	changed, err := writeGeneratedFile(targetPath, buf.Bytes())
+	if err != nil {
+		return err
+	}
+	if changed {
+		fmt.Println("Generated Kotlin strings: " + targetPath)
+	}
+	return nil
+}
+
+// kotlinParamName returns the parameter name of a placeholder, avoiding the names used by
+// the generated functions
+func kotlinParamName(p codegenParam) string {
+	if !p.Named {
+		return p.Name
+	}
+	name := camelCase(p.Name)
+	if name == "context" || name == "count" {
+		name += "Value"
+	}
+	return kotlinIdentifier(name)
+}
+
+// kotlinIdentifier escapes keywords with backticks
+func kotlinIdentifier(name string) string {
+	if slices.Contains(kotlinKeywords, name) {
+		return "`" + name + "`"
+	}
+	return name
+}
+
+// kotlinDocQuote quotes a value for a KDoc comment on a single line
+func kotlinDocQuote(value string) string {
+	return strings.ReplaceAll(goDocQuote(value), "*/", "*\\/")
+}
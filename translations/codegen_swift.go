@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// SwiftOptions configures the generated Swift enum
+type SwiftOptions struct {
+	EnumName string
+}
+
+// swiftParamTypes maps parameter types to Swift types
+var swiftParamTypes = map[string]string{
+	paramString: "String",
+	paramInt:    "Int",
+	paramFloat:  "Double",
+}
+
+var swiftKeywords = []string{
+	"Any", "Self", "as", "associatedtype", "break", "case", "catch", "class", "continue", "default", "defer",
+	"deinit", "do", "else", "enum", "extension", "fallthrough", "false", "fileprivate", "for", "func", "guard",
+	"if", "import", "in", "init", "inout", "internal", "is", "let", "nil", "open", "operator", "private",
+	"protocol", "public", "repeat", "rethrows", "return", "self", "static", "struct", "subscript", "super",
+	"switch", "throw", "throws", "true", "try", "typealias", "var", "where", "while",
+}
+
+// GenerateSwiftStrings writes a Swift enum with a LocalizedStringResource per plain string
+// and a typed function per string with placeholders or plural forms, so removed keys become
+// compile errors in the app
+func GenerateSwiftStrings(tm *Translations, app, targetPath string, opts SwiftOptions) error {
+	keys := collectCodegenKeys(tm, app)
+	if len(keys) == 0 {
+		fmt.Printf("No %s keys to generate Swift strings for\n", app)
+		return nil
+	}
+
+	keyNames := make([]string, 0, len(keys))
+	for _, k := range keys {
+		keyNames = append(keyNames, k.Key)
+	}
+	members := uniqueIdentifiers(app, keyNames, camelCase)
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by the zeitkapsl translations tool from the translations CSV. DO NOT EDIT.\n\n")
+	buf.WriteString("import Foundation\n\n")
+	fmt.Fprintf(&buf, "enum %s {\n", opts.EnumName)
+
+	for i, k := range keys {
+		name, ok := members[k.Key]
+		if !ok {
+			continue
+		}
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+
+		if !k.Plural && len(k.Params) == 0 {
+			fmt.Fprintf(&buf, "    /// %s\n", goDocQuote(k.Source))
+			fmt.Fprintf(&buf, "    static let %s = LocalizedStringResource(%s)\n", swiftIdentifier(name), strconv.Quote(k.Key))
+			continue
+		}
+
+		params := make([]string, 0)
+		if k.Plural {
+			params = append(params, "count: Int")
+		}
+		args := make([]string, 0)
+		replacements := make([]string, 0)
+		for _, p := range k.Params {
+			paramName := swiftParamName(p)
+			if p.Named {
+				params = append(params, paramName+": "+swiftParamTypes[p.Type])
+				replacements = append(replacements, fmt.Sprintf(".replacingOccurrences(of: %s, with: %s)", strconv.Quote("{"+p.Name+"}"), paramName))
+			} else {
+				params = append(params, "_ "+paramName+": "+swiftParamTypes[p.Type])
+				args = append(args, paramName)
+			}
+		}
+
+		if k.Plural {
+			if passesCount(k) {
+				args = append([]string{"count"}, args...)
+			}
+			fmt.Fprintf(&buf, "    /// %s, or its singular form for a count of 1\n", goDocQuote(k.Other))
+			fmt.Fprintf(&buf, "    static func %s(%s) -> String {\n", swiftIdentifier(name), strings.Join(params, ", "))
+			// The export writes the pair as plural variations of the base key, which are
+			// chosen by the count when the format is applied
+			fmt.Fprintf(&buf, "        let format = NSLocalizedString(%s, comment: \"\")\n", strconv.Quote(k.Key))
+			fmt.Fprintf(&buf, "        return String.localizedStringWithFormat(format, %s)%s\n    }\n", strings.Join(args, ", "), strings.Join(replacements, ""))
+			continue
+		}
+
+		fmt.Fprintf(&buf, "    /// %s\n", goDocQuote(k.Source))
+		fmt.Fprintf(&buf, "    static func %s(%s) -> String {\n", swiftIdentifier(name), strings.Join(params, ", "))
+		fmt.Fprintf(&buf, "        %s\n    }\n", swiftFormatExpression(strconv.Quote(k.Key), args, replacements))
+	}
+	buf.WriteString("}\n")
+
+	if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
+		return err
+	}
+	changed, err := writeGeneratedFile(targetPath, buf.Bytes())
+	if err != nil {
+		return err
+	}
+	if changed {
+		fmt.Println("Generated Swift strings: " + targetPath)
+	}
+	return nil
+}
+
+// swiftFormatExpression returns the expression looking up and formatting a localized string
+func swiftFormatExpression(key string, args, replacements []string) string {
+	expression := "String(localized: " + key + ")"
+	if len(args) > 0 {
+		expression = "String(format: " + expression + ", " + strings.Join(args, ", ") + ")"
+	}
+	return expression + strings.Join(replacements, "")
+}
+
+// swiftParamName returns the parameter name of a placeholder
+func swiftParamName(p codegenParam) string {
+	if !p.Named {
+		return p.Name
+	}
+	name := camelCase(p.Name)
+	if name == "count" || name == "key" {
+		name += "Value"
+	}
+	return swiftIdentifier(name)
+}
+
+// swiftIdentifier escapes keywords with backticks
+func swiftIdentifier(name string) string {
+	if slices.Contains(swiftKeywords, name) {
+		return "`" + name + "`"
+	}
+	return name
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// TypeScriptOptions configures the generated TypeScript file
+type TypeScriptOptions struct {
+	PluralStyle string // plural style of the JSON files, see JSONOptions
+}
+
+var typeScriptIdentifierPattern = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// typeScriptParamTypes maps parameter types to TypeScript types
+var typeScriptParamTypes = map[string]string{
+	paramString: "string",
+	paramInt:    "number",
+	paramFloat:  "number",
+}
+
+// typeScriptKey is a key of the JSON files with its parameter type, empty without parameters
+type typeScriptKey struct {
+	Key    string
+	Params string
+}
+
+// GenerateTypeScriptKeys writes a TypeScript file with the supported locales, a union of all
+// keys and the parameters of keys with placeholders. Printf placeholders are passed as a
+// tuple, named placeholders as an object, keys with both as an object with the tuple in args.
+func GenerateTypeScriptKeys(tm *Translations, app, targetPath string, opts TypeScriptOptions) error {
+	keys := make([]typeScriptKey, 0)
+	for _, k := range collectCodegenKeys(tm, app) {
+		if !k.Plural {
+			keys = append(keys, typeScriptKey{Key: k.Key, Params: typeScriptParams(k.Params, false)})
+			continue
+		}
+		// Legacy plurals are separate keys, the other styles select the form by count
+		if opts.PluralStyle == "" || opts.PluralStyle == PluralStyleLegacy {
+			keys = append(keys,
+				typeScriptKey{Key: k.SingularKey, Params: typeScriptParams(placeholderParams(k.One, false), false)},
+				typeScriptKey{Key: k.PluralKey, Params: typeScriptParams(placeholderParams(k.Other, false), false)})
+			continue
+		}
+		keys = append(keys, typeScriptKey{Key: k.Key, Params: typeScriptParams(k.Params, true)})
+	}
+	if len(keys) == 0 {
+		fmt.Printf("No %s keys to generate TypeScript types for\n", app)
+		return nil
+	}
+	slices.SortStableFunc(keys, func(a, b typeScriptKey) int { return strings.Compare(a.Key, b.Key) })
+	keys = slices.CompactFunc(keys, func(a, b typeScriptKey) bool { return a.Key == b.Key })
+
+	locales := make([]string, 0)
+	for _, lang := range tm.Languages {
+		if isPseudoLocale(lang) {
+			continue
+		}
+		for _, row := range tm.GetTranslationsForApp(app) {
+			if row.Values[lang] != "" {
+				locales = append(locales, lang)
+				break
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by the zeitkapsl translations tool from the translations CSV. DO NOT EDIT.\n\n")
+
+	buf.WriteString("export const locales = [")
+	for i, lang := range locales {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(typeScriptString(lang))
+	}
+	buf.WriteString("] as const;\n\nexport type Locale = (typeof locales)[number];\n\n")
+
+	buf.WriteString("export type TranslationKey =\n")
+	for i, k := range keys {
+		fmt.Fprintf(&buf, "  | %s", typeScriptString(k.Key))
+		if i == len(keys)-1 {
+			buf.WriteString(";\n\n")
+		} else {
+			buf.WriteString("\n")
+		}
+	}
+
+	buf.WriteString("export interface TranslationParams {\n")
+	for _, k := range keys {
+		if k.Params != "" {
+			fmt.Fprintf(&buf, "  %s: %s;\n", typeScriptString(k.Key), k.Params)
+		}
+	}
+	buf.WriteString("}\n\n")
+
+	buf.WriteString("export type TranslationArgs<K extends TranslationKey> = K extends keyof TranslationParams\n")
+	buf.WriteString("  ? [params: TranslationParams[K]]\n  : [];\n")
+
+	if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
+		return err
+	}
+	changed, err := writeGeneratedFile(targetPath, buf.Bytes())
+	if err != nil {
+		return err
+	}
+	if changed {
+		fmt.Println("Generated TypeScript keys: " + targetPath)
+	}
+	return nil
+}
+
+// typeScriptParams returns the parameter type of a key, empty if it has no parameters
+func typeScriptParams(params []codegenParam, plural bool) string {
+	positional := make([]string, 0)
+	named := make([]string, 0)
+	if plural {
+		named = append(named, "count: number")
+	}
+	for _, p := range params {
+		paramType := typeScriptParamTypes[p.Type]
+		if !p.Named {
+			positional = append(positional, paramType)
+			continue
+		}
+		name := p.Name
+		if !typeScriptIdentifierPattern.MatchString(name) {
+			name = typeScriptString(name)
+		}
+		named = append(named, name+": "+paramType)
+	}
+
+	tuple := "[" + strings.Join(positional, ", ") + "]"
+	switch {
+	case len(named) == 0 && len(positional) == 0:
+		return ""
+	case len(named) == 0:
+		return tuple
+	case len(positional) > 0:
+		named = append(named, "args: "+tuple)
+	}
+	return "{ " + strings.Join(named, "; ") + " }"
+}
+
+// typeScriptString returns a double quoted TypeScript string literal
+func typeScriptString(value string) string {
+	quoted, err := marshalNoEscape(value)
+	if err != nil {
+		return `""`
+	}
+	return string(quoted)
+}
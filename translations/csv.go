@@ -10,6 +10,55 @@ import (
 // DefaultCSVFile is the default filename for CSV exports
 const DefaultCSVFile = "translations.csv"
 
+// csvMetaColumn is an optional per-row column between comment and the languages
+type csvMetaColumn struct {
+	Name string
+	Get  func(row *TranslationRow) string
+	Set  func(row *TranslationRow, value string)
+}
+
+// csvMetaColumns are only written if at least one row has a value
+var csvMetaColumns = []csvMetaColumn{
+	{
+		Name: "placeholders",
+		Get:  func(row *TranslationRow) string { return row.Placeholders },
+		Set:  func(row *TranslationRow, value string) { row.Placeholders = value },
+	},
+	{
+		Name: "inherits",
+		Get:  func(row *TranslationRow) string { return row.Inherits },
+		Set:  func(row *TranslationRow, value string) { row.Inherits = value },
+	},
+	{
+		Name: "max_length",
+		Get:  func(row *TranslationRow) string { return row.MaxLength },
+		Set:  func(row *TranslationRow, value string) { row.MaxLength = value },
+	},
+}
+
+// usedMetaColumns returns the meta columns that at least one row has a value for
+func usedMetaColumns(tm *Translations) []csvMetaColumn {
+	result := make([]csvMetaColumn, 0)
+	for _, column := range csvMetaColumns {
+		for i := range tm.Translations {
+			if column.Get(&tm.Translations[i]) != "" {
+				result = append(result, column)
+				break
+			}
+		}
+	}
+	return result
+}
+
+func findMetaColumn(name string) *csvMetaColumn {
+	for i, column := range csvMetaColumns {
+		if column.Name == name {
+			return &csvMetaColumns[i]
+		}
+	}
+	return nil
+}
+
 // SaveToCSV saves a translation set to a CSV file
 func SaveToCSV(tm *Translations, filename string) error {
 	if filename == "" {
@@ -29,8 +78,13 @@ func SaveToCSV(tm *Translations, filename string) error {
 	// Sort languages
 	sort.Strings(tm.Languages)
 
+	metaColumns := usedMetaColumns(tm)
+
 	// Write header
 	header := []string{"app", "key", "comment"}
+	for _, column := range metaColumns {
+		header = append(header, column.Name)
+	}
 	for _, lang := range tm.Languages {
 		header = append(header, lang)
 	}
@@ -41,6 +95,9 @@ func SaveToCSV(tm *Translations, filename string) error {
 	// Write translations
 	for _, trans := range tm.Translations {
 		record := []string{trans.App, trans.Key, trans.Comment}
+		for _, column := range metaColumns {
+			record = append(record, column.Get(&trans))
+		}
 		// Add translation values for each language
 		for _, lang := range tm.Languages {
 			record = append(record, trans.Values[lang])
@@ -84,11 +141,16 @@ func LoadFromCSV(tm *Translations, filename string) error {
 		return fmt.Errorf("invalid CSV header: expected at least 3 columns with key, comment, type")
 	}
 
-	// Extract languages from header
+	// Extract meta columns and languages from header
 	var languages []string
 	langMap := make(map[string]int) // language -> starting column index
+	metaMap := make(map[int]*csvMetaColumn)
 
 	for i := 3; i < len(header); i++ {
+		if column := findMetaColumn(header[i]); column != nil {
+			metaMap[i] = column
+			continue
+		}
 		lang := header[i]
 		languages = append(languages, lang)
 		langMap[lang] = i
@@ -117,6 +179,12 @@ func LoadFromCSV(tm *Translations, filename string) error {
 			Values:  make(map[string]string),
 		}
 
+		for colIdx, column := range metaMap {
+			if colIdx < len(record) {
+				column.Set(&trans, record[colIdx])
+			}
+		}
+
 		// Parse language translations
 		for _, lang := range languages {
 			colIdx, ok := langMap[lang]
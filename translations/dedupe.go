@@ -0,0 +1,204 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+var trailingPunctuationPattern = regexp.MustCompile(`[\s.!?:;…]+$`)
+
+// DuplicateGroup is a set of rows whose English texts are identical after normalization
+type DuplicateGroup struct {
+	Text       string // English text of the first row
+	Normalized string
+	Rows       []TranslationRow
+	// Variants maps a language to its distinct values and the rows using them
+	Variants map[string]map[string][]string
+	// Canonical is the most used value per language
+	Canonical map[string]string
+	// SharedKey is an existing shared row with the same text, or a suggested key for a new one
+	SharedKey    string
+	SharedExists bool
+}
+
+// DedupeOptions limits which rows and languages are compared
+type DedupeOptions struct {
+	Apps              []string
+	Languages         []string
+	DisagreementsOnly bool
+}
+
+// Disagreements returns the languages in which the rows of the group are translated differently
+func (g DuplicateGroup) Disagreements() []string {
+	result := make([]string, 0)
+	for lang, values := range g.Variants {
+		if len(values) > 1 {
+			result = append(result, lang)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// normalizeSource makes texts comparable: lower case, single spaces, no trailing punctuation
+// and the same style for all placeholders
+func normalizeSource(text string) string {
+	text = placeholderPattern.ReplaceAllString(text, "{}")
+	text = strings.ToLower(strings.Join(strings.Fields(text), " "))
+	return trailingPunctuationPattern.ReplaceAllString(text, "")
+}
+
+// FindDuplicates groups rows with the same normalized English text. Rows that already inherit
+// and plural halves are not compared, rows of the shared app only provide the suggested key.
+func FindDuplicates(tm *Translations, opts DedupeOptions) []DuplicateGroup {
+	groups := make(map[string]*DuplicateGroup)
+	shared := make(map[string]string)
+	sharedKeys := make(map[string]bool)
+
+	for _, row := range tm.Translations {
+		source := row.Values["en"]
+		if source == "" || row.IsPlural() || row.Inherits != "" {
+			continue
+		}
+		normalized := normalizeSource(source)
+		if normalized == "" {
+			continue
+		}
+		if row.App == SharedApp {
+			sharedKeys[row.Key] = true
+			if _, ok := shared[normalized]; !ok {
+				shared[normalized] = row.Key
+			}
+			continue
+		}
+		if len(opts.Apps) > 0 && !slices.Contains(opts.Apps, row.App) {
+			continue
+		}
+
+		group, ok := groups[normalized]
+		if !ok {
+			group = &DuplicateGroup{Text: source, Normalized: normalized}
+			groups[normalized] = group
+		}
+		group.Rows = append(group.Rows, row)
+	}
+
+	result := make([]DuplicateGroup, 0)
+	for normalized, group := range groups {
+		if len(group.Rows) < 2 {
+			continue
+		}
+
+		group.Variants = make(map[string]map[string][]string)
+		group.Canonical = make(map[string]string)
+		for _, lang := range tm.Languages {
+			if len(opts.Languages) > 0 && !slices.Contains(opts.Languages, lang) {
+				continue
+			}
+			values := make(map[string][]string)
+			for _, row := range group.Rows {
+				if value := row.Values[lang]; value != "" {
+					values[value] = append(values[value], row.App+":"+row.Key)
+				}
+			}
+			if len(values) == 0 {
+				continue
+			}
+			group.Variants[lang] = values
+			group.Canonical[lang] = mostUsedValue(values)
+		}
+
+		if key, ok := shared[normalized]; ok {
+			group.SharedKey, group.SharedExists = key, true
+		} else {
+			group.SharedKey = suggestSharedKey(normalized, sharedKeys)
+			sharedKeys[group.SharedKey] = true
+		}
+
+		if opts.DisagreementsOnly && len(group.Disagreements()) == 0 {
+			continue
+		}
+		result = append(result, *group)
+	}
+
+	// Groups with the most disagreements and rows first
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if da, db := len(a.Disagreements()), len(b.Disagreements()); da != db {
+			return da > db
+		}
+		if len(a.Rows) != len(b.Rows) {
+			return len(a.Rows) > len(b.Rows)
+		}
+		return a.Normalized < b.Normalized
+	})
+	return result
+}
+
+// mostUsedValue returns the value used by most rows, the alphabetically first one on a tie
+func mostUsedValue(values map[string][]string) string {
+	best := ""
+	for value, rows := range values {
+		if best == "" || len(rows) > len(values[best]) || (len(rows) == len(values[best]) && value < best) {
+			best = value
+		}
+	}
+	return best
+}
+
+// suggestSharedKey derives an unused shared key from a normalized text, e.g. "delete album"
+// → delete_album
+func suggestSharedKey(normalized string, taken map[string]bool) string {
+	words := identifierWords(strings.ReplaceAll(normalized, "{}", ""))
+	if len(words) > 5 {
+		words = words[:5]
+	}
+	key := strings.ToLower(strings.Join(words, "_"))
+	if key == "" {
+		key = "text"
+	}
+
+	result := key
+	for i := 2; taken[result]; i++ {
+		result = fmt.Sprintf("%s_%d", key, i)
+	}
+	return result
+}
+
+// PrintDuplicateGroups prints the groups with their disagreements and the link commands that
+// consolidate them
+func PrintDuplicateGroups(groups []DuplicateGroup) {
+	for _, group := range groups {
+		fmt.Printf("%q (%d rows)\n", group.Text, len(group.Rows))
+		for _, row := range group.Rows {
+			fmt.Printf("  %s:%s\n", row.App, row.Key)
+		}
+
+		disagreements := group.Disagreements()
+		for _, lang := range disagreements {
+			variants := make([]string, 0)
+			for value, rows := range group.Variants[lang] {
+				variants = append(variants, fmt.Sprintf("%q (%s)", value, strings.Join(rows, ", ")))
+			}
+			sort.Strings(variants)
+			fmt.Printf("  %s disagrees: %s\n", lang, strings.Join(variants, " vs "))
+			fmt.Printf("    suggestion: keep %q\n", group.Canonical[lang])
+		}
+
+		if group.SharedExists {
+			fmt.Printf("  suggestion: link to the existing shared:%s\n", group.SharedKey)
+		} else if len(disagreements) == 0 {
+			fmt.Printf("  suggestion: translations agree, link to a new shared:%s\n", group.SharedKey)
+		} else {
+			fmt.Printf("  suggestion: agree on one translation, then link to a new shared:%s\n", group.SharedKey)
+		}
+		for _, row := range group.Rows {
+			fmt.Printf("    link --app %s --key %s --to shared:%s\n", row.App, row.Key, group.SharedKey)
+		}
+		fmt.Println()
+	}
+	fmt.Printf("Found %d groups of duplicate strings\n", len(groups))
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// FakeTranslator implements TranslationService without any network access.
+// Texts found in the dictionary are translated by lookup, all others are pseudo-translated
+// by prefixing the target language, e.g. "Delete" → "[de] Delete". Translating back to the
+// source language strips the prefix again, so round trips are lossless.
+type FakeTranslator struct {
+	// Dictionary maps target language → source text → translation
+	Dictionary map[string]map[string]string
+}
+
+// NewFakeTranslator creates a fake translator, optionally with a dictionary loaded from a JSON file
+func NewFakeTranslator(dictionaryFile string) (*FakeTranslator, error) {
+	fake := &FakeTranslator{Dictionary: make(map[string]map[string]string)}
+	if dictionaryFile == "" {
+		return fake, nil
+	}
+
+	data, err := os.ReadFile(dictionaryFile)
+	if err != nil {
+		return nil, fmt.Errorf("error reading fake dictionary %s: %v", dictionaryFile, err)
+	}
+	if err := json.Unmarshal(data, &fake.Dictionary); err != nil {
+		return nil, fmt.Errorf("error parsing fake dictionary %s: %v", dictionaryFile, err)
+	}
+	return fake, nil
+}
+
+func (f *FakeTranslator) Name() string {
+	return "Fake"
+}
+
+func (f *FakeTranslator) Translate(text, sourceLang, targetLang string) (string, error) {
+	if translated, ok := f.Dictionary[targetLang][text]; ok {
+		return translated, nil
+	}
+
+	// Strip a pseudo-translation prefix of an earlier run
+	if strings.HasPrefix(text, "[") {
+		if end := strings.Index(text, "] "); end > 0 && !strings.Contains(text[:end], " ") {
+			text = text[end+2:]
+		}
+	}
+
+	if targetLang == "en" {
+		return text, nil
+	}
+	return fmt.Sprintf("[%s] %s", targetLang, text), nil
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFakeTranslator(t *testing.T) {
+	fake := &FakeTranslator{Dictionary: map[string]map[string]string{"de": {"Delete": "Löschen"}}}
+	tests := []struct {
+		text, source, target string
+		want                 string
+	}{
+		{"Delete", "en", "de", "Löschen"},
+		{"Cancel", "en", "de", "[de] Cancel"},
+		{"[de] Cancel", "de", "en", "Cancel"},
+		{"[de] Cancel", "de", "fr", "[fr] Cancel"},
+		{"[not a prefix] Cancel", "en", "de", "[de] [not a prefix] Cancel"},
+	}
+	for _, tt := range tests {
+		got, err := fake.Translate(tt.text, tt.source, tt.target)
+		if err != nil {
+			t.Fatalf("Translate(%q): %v", tt.text, err)
+		}
+		if got != tt.want {
+			t.Errorf("Translate(%q, %s → %s) = %q, want %q", tt.text, tt.source, tt.target, got, tt.want)
+		}
+	}
+}
+
+func TestNewFakeTranslator(t *testing.T) {
+	dir := t.TempDir()
+	dictionary := filepath.Join(dir, "dictionary.json")
+	if err := os.WriteFile(dictionary, []byte(`{"de": {"Delete": "Löschen"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	fake, err := NewFakeTranslator(dictionary)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := fake.Translate("Delete", "en", "de"); got != "Löschen" {
+		t.Errorf("Translate = %q, want %q", got, "Löschen")
+	}
+
+	if _, err := NewFakeTranslator(filepath.Join(dir, "missing.json")); err == nil {
+		t.Errorf("NewFakeTranslator of a missing file: no error")
+	}
+	empty, err := NewFakeTranslator("")
+	if err != nil || len(empty.Dictionary) != 0 {
+		t.Errorf("NewFakeTranslator(\"\") = %+v, %v, want an empty dictionary", empty, err)
+	}
+}
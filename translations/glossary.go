@@ -0,0 +1,161 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// DefaultGlossaryFile is the default filename for the terminology list
+const DefaultGlossaryFile = "glossary.json"
+
+// GlossaryTerm is an English term and how it has to be translated
+type GlossaryTerm struct {
+	Term string `json:"term"`
+	// Translations is the required translation per language, a language falls back to its base
+	// language (de_AT → de)
+	Translations map[string]string `json:"translations,omitempty"`
+	// Keep marks names that are never translated and always written exactly like Term
+	Keep bool `json:"keep,omitempty"`
+}
+
+// Glossary is the terminology every translation is checked against
+type Glossary struct {
+	Terms []GlossaryTerm `json:"terms"`
+	// Forbidden lists the terms that must not appear per language, "*" applies to every language
+	Forbidden map[string][]string `json:"forbidden,omitempty"`
+}
+
+// TerminologyOptions limits which rows and languages are checked
+type TerminologyOptions struct {
+	Apps      []string
+	Languages []string
+}
+
+// LoadGlossary loads the terminology list, a missing file yields an empty glossary
+func LoadGlossary(filename string) (*Glossary, error) {
+	if filename == "" {
+		filename = DefaultGlossaryFile
+	}
+
+	glossary := &Glossary{}
+	data, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return glossary, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading glossary %s: %v", filename, err)
+	}
+	if err := json.Unmarshal(data, glossary); err != nil {
+		return nil, fmt.Errorf("error parsing glossary %s: %v", filename, err)
+	}
+	return glossary, nil
+}
+
+// forbiddenTerms returns the terms that must not appear in a language
+func (g *Glossary) forbiddenTerms(lang string) []string {
+	terms := append([]string{}, g.Forbidden["*"]...)
+	terms = append(terms, g.Forbidden[lang]...)
+	if base := baseLanguage(lang); base != lang {
+		terms = append(terms, g.Forbidden[base]...)
+	}
+	return terms
+}
+
+// translation returns the required translation of a term in a language, if any
+func (t GlossaryTerm) translation(lang string) string {
+	if value, ok := t.Translations[lang]; ok {
+		return value
+	}
+	return t.Translations[baseLanguage(lang)]
+}
+
+// termPattern matches a term case-insensitively at the start of a word, so "collection" also
+// matches "Collections" but not "recollection"
+func termPattern(term string) *regexp.Regexp {
+	return regexp.MustCompile(`(?i)(?:^|[^\p{L}\p{N}])(` + regexp.QuoteMeta(term) + `)`)
+}
+
+// termOccurrences returns every occurrence of a term in a text as written
+func termOccurrences(pattern *regexp.Regexp, text string) []string {
+	result := make([]string, 0)
+	for _, match := range pattern.FindAllStringSubmatch(text, -1) {
+		result = append(result, match[1])
+	}
+	return result
+}
+
+// glossaryText removes markup and placeholders, which are not checked against the glossary
+func glossaryText(text string) string {
+	text = htmlTagPattern.ReplaceAllString(text, " ")
+	return placeholderPattern.ReplaceAllString(text, " ")
+}
+
+// CheckTerminology checks every value against the glossary: required translations of terms
+// used in the English source, names that are kept as they are and forbidden terms
+func CheckTerminology(tm *Translations, glossary *Glossary, opts TerminologyOptions) []ValidationIssue {
+	sourceLang := "en"
+	issues := make([]ValidationIssue, 0)
+
+	patterns := make(map[string]*regexp.Regexp)
+	pattern := func(term string) *regexp.Regexp {
+		if _, ok := patterns[term]; !ok {
+			patterns[term] = termPattern(term)
+		}
+		return patterns[term]
+	}
+
+	for _, row := range tm.Translations {
+		if len(opts.Apps) > 0 && !slices.Contains(opts.Apps, row.App) {
+			continue
+		}
+		source := glossaryText(row.Values[sourceLang])
+
+		for _, lang := range tm.Languages {
+			if len(opts.Languages) > 0 && !slices.Contains(opts.Languages, lang) {
+				continue
+			}
+			if row.Values[lang] == "" {
+				continue
+			}
+			value := glossaryText(row.Values[lang])
+
+			add := func(severity, message string) {
+				issues = append(issues, ValidationIssue{App: row.App, Key: row.Key, Lang: lang, Severity: severity, Message: message})
+			}
+
+			for _, term := range glossary.Terms {
+				inSource := pattern(term.Term).MatchString(source)
+
+				if term.Keep {
+					occurrences := termOccurrences(pattern(term.Term), value)
+					for _, occurrence := range occurrences {
+						if occurrence != term.Term {
+							add(SeverityError, fmt.Sprintf("%q must be written as %q", occurrence, term.Term))
+						}
+					}
+					if lang != sourceLang && inSource && len(occurrences) == 0 {
+						add(SeverityError, fmt.Sprintf("%q must not be translated", term.Term))
+					}
+					continue
+				}
+
+				// Substring match, so compounds like "Fotosammlung" count as well
+				required := term.translation(lang)
+				if lang != sourceLang && inSource && required != "" && !strings.Contains(strings.ToLower(value), strings.ToLower(required)) {
+					add(SeverityWarning, fmt.Sprintf("%q should be translated as %q", term.Term, required))
+				}
+			}
+
+			for _, forbidden := range glossary.forbiddenTerms(lang) {
+				if occurrences := termOccurrences(pattern(forbidden), value); len(occurrences) > 0 {
+					add(SeverityError, fmt.Sprintf("forbidden term %q", occurrences[0]))
+				}
+			}
+		}
+	}
+	return issues
+}
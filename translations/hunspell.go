@@ -0,0 +1,466 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// iso885915 are the characters in which ISO 8859-15 differs from ISO 8859-1
+var iso885915 = map[byte]rune{0xA4: '€', 0xA6: 'Š', 0xA8: 'š', 0xB4: 'Ž', 0xB8: 'ž', 0xBC: 'Œ', 0xBD: 'œ', 0xBE: 'Ÿ'}
+
+// affixRule is a single PFX or SFX rule of a Hunspell .aff file
+type affixRule struct {
+	Flag      string
+	Strip     string
+	Affix     string
+	Condition *regexp.Regexp
+	Cross     bool   // may be combined with an affix of the other kind
+	Next      string // continuation class, the flags of suffixes that may follow
+}
+
+// HunspellDictionary checks words against a Hunspell .dic/.aff pair. It supports the common
+// subset: prefixes and suffixes with cross products, two suffixes through continuation classes,
+// flag aliases, forbidden words, NEEDAFFIX, ONLYINCOMPOUND and compounds through COMPOUNDFLAG or
+// COMPOUNDBEGIN/MIDDLE/END. Not supported are continuation classes of prefixes and flags like
+// NEEDAFFIX within continuation classes, KEEPCASE, CHECKSHARPS, COMPOUNDRULE and the compound
+// check options (CHECKCOMPOUND*), so a dictionary relying on them accepts some misspellings.
+type HunspellDictionary struct {
+	words    map[string][]string // word → flags of each homonym
+	prefixes map[string][]affixRule
+	suffixes map[string][]affixRule
+
+	flagType       string
+	aliases        []string
+	ignore         string
+	forbidden      string
+	needAffix      string
+	onlyInCompound string
+	compound       string
+	compoundBegin  string
+	compoundMiddle string
+	compoundEnd    string
+	compoundMin    int
+}
+
+// LoadHunspellDictionary loads a dictionary from its .aff and .dic files. The encoding given by
+// SET may be UTF-8, ISO8859-1 or ISO8859-15.
+func LoadHunspellDictionary(affFile, dicFile string) (*HunspellDictionary, error) {
+	d := &HunspellDictionary{
+		words:       make(map[string][]string),
+		prefixes:    make(map[string][]affixRule),
+		suffixes:    make(map[string][]affixRule),
+		compoundMin: 3,
+	}
+
+	affLines, err := readHunspellFile(affFile, "")
+	if err != nil {
+		return nil, err
+	}
+	encoding := ""
+	for _, line := range affLines {
+		if fields := strings.Fields(line); len(fields) == 2 && fields[0] == "SET" {
+			encoding = strings.ToUpper(fields[1])
+		}
+	}
+	if encoding != "" {
+		if affLines, err = readHunspellFile(affFile, encoding); err != nil {
+			return nil, err
+		}
+	}
+	if err := d.parseAff(affLines); err != nil {
+		return nil, fmt.Errorf("error parsing %s: %v", affFile, err)
+	}
+
+	dicLines, err := readHunspellFile(dicFile, encoding)
+	if err != nil {
+		return nil, err
+	}
+	for i, line := range dicLines {
+		// The first line is the approximate number of words
+		if i == 0 || strings.TrimSpace(line) == "" || strings.HasPrefix(line, "\t") {
+			continue
+		}
+		d.addWord(line)
+	}
+	return d, nil
+}
+
+// readHunspellFile reads the lines of a dictionary file in the given encoding
+func readHunspellFile(filename, encoding string) ([]string, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %v", filename, err)
+	}
+
+	text := ""
+	switch encoding {
+	case "", "UTF-8", "UTF8":
+		text = strings.TrimPrefix(string(data), "\ufeff")
+	case "ISO8859-1", "ISO8859-15":
+		runes := make([]rune, len(data))
+		for i, b := range data {
+			runes[i] = rune(b)
+			if r, ok := iso885915[b]; ok && encoding == "ISO8859-15" {
+				runes[i] = r
+			}
+		}
+		text = string(runes)
+	default:
+		return nil, fmt.Errorf("unsupported encoding %s in %s", encoding, filename)
+	}
+
+	lines := make([]string, 0)
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		lines = append(lines, strings.TrimRight(scanner.Text(), "\r"))
+	}
+	return lines, scanner.Err()
+}
+
+// parseAff reads the options and affix rules this checker supports
+func (d *HunspellDictionary) parseAff(lines []string) error {
+	// The header of an affix class comes before its rules, so the cross product flag is known
+	cross := make(map[string]bool)
+
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		switch fields[0] {
+		case "FLAG":
+			d.flagType = fields[1]
+		case "AF":
+			// The first AF line is the number of aliases, aliases are numbered from 1
+			if d.aliases == nil {
+				d.aliases = []string{""}
+				continue
+			}
+			d.aliases = append(d.aliases, fields[1])
+		case "IGNORE":
+			d.ignore = fields[1]
+		case "FORBIDDENWORD":
+			d.forbidden = d.firstFlag(fields[1])
+		case "NEEDAFFIX":
+			d.needAffix = d.firstFlag(fields[1])
+		case "ONLYINCOMPOUND":
+			d.onlyInCompound = d.firstFlag(fields[1])
+		case "COMPOUNDFLAG":
+			d.compound = d.firstFlag(fields[1])
+		case "COMPOUNDBEGIN":
+			d.compoundBegin = d.firstFlag(fields[1])
+		case "COMPOUNDMIDDLE":
+			d.compoundMiddle = d.firstFlag(fields[1])
+		case "COMPOUNDEND":
+			d.compoundEnd = d.firstFlag(fields[1])
+		case "COMPOUNDMIN":
+			if value, err := strconv.Atoi(fields[1]); err == nil && value > 0 {
+				d.compoundMin = value
+			}
+		case "PFX", "SFX":
+			if len(fields) == 4 {
+				cross[fields[0]+fields[1]] = fields[2] == "Y"
+				continue
+			}
+			if len(fields) < 5 {
+				return fmt.Errorf("invalid affix rule %q", line)
+			}
+
+			rule := affixRule{Flag: fields[1], Cross: cross[fields[0]+fields[1]]}
+			if fields[2] != "0" {
+				rule.Strip = fields[2]
+			}
+			affix, next, _ := strings.Cut(fields[3], "/")
+			rule.Next = d.resolveAlias(next)
+			if affix != "0" {
+				rule.Affix = d.withoutIgnored(affix)
+			}
+
+			condition := fields[4]
+			if condition == "." {
+				condition = ""
+			}
+			pattern := "(?:" + condition + ")$"
+			if fields[0] == "PFX" {
+				pattern = "^(?:" + condition + ")"
+			}
+			compiled, err := regexp.Compile(pattern)
+			if err != nil {
+				return fmt.Errorf("invalid condition in %q: %v", line, err)
+			}
+			rule.Condition = compiled
+
+			if fields[0] == "PFX" {
+				d.prefixes[rule.Affix] = append(d.prefixes[rule.Affix], rule)
+			} else {
+				d.suffixes[rule.Affix] = append(d.suffixes[rule.Affix], rule)
+			}
+		}
+	}
+	return nil
+}
+
+// addWord adds a .dic line "word/flags", morphological fields are ignored
+func (d *HunspellDictionary) addWord(line string) {
+	line, _, _ = strings.Cut(line, "\t")
+	entry := strings.Fields(line)
+	if len(entry) == 0 {
+		return
+	}
+
+	word, flags := entry[0], ""
+	if i := strings.LastIndex(word, "/"); i > 0 && word[i-1] != '\\' {
+		word, flags = word[:i], word[i+1:]
+	}
+	word = d.withoutIgnored(strings.ReplaceAll(word, `\/`, "/"))
+	d.words[word] = append(d.words[word], d.resolveAlias(flags))
+}
+
+// resolveAlias returns the flags of an AF alias number, other flag fields are returned as they are
+func (d *HunspellDictionary) resolveAlias(flags string) string {
+	if index, err := strconv.Atoi(flags); err == nil && index > 0 && index < len(d.aliases) {
+		return d.aliases[index]
+	}
+	return flags
+}
+
+// flags splits a flag field according to the FLAG type
+func (d *HunspellDictionary) flags(field string) []string {
+	result := make([]string, 0)
+	switch d.flagType {
+	case "long":
+		runes := []rune(field)
+		for i := 0; i+1 < len(runes); i += 2 {
+			result = append(result, string(runes[i:i+2]))
+		}
+	case "num":
+		for _, flag := range strings.Split(field, ",") {
+			if flag = strings.TrimSpace(flag); flag != "" {
+				result = append(result, flag)
+			}
+		}
+	default:
+		for _, r := range field {
+			result = append(result, string(r))
+		}
+	}
+	return result
+}
+
+// firstFlag returns the flag of an option like FORBIDDENWORD
+func (d *HunspellDictionary) firstFlag(field string) string {
+	if flags := d.flags(field); len(flags) > 0 {
+		return flags[0]
+	}
+	return ""
+}
+
+// hasFlag reports whether a flag field contains a flag
+func (d *HunspellDictionary) hasFlag(field, flag string) bool {
+	if flag == "" {
+		return false
+	}
+	for _, f := range d.flags(field) {
+		if f == flag {
+			return true
+		}
+	}
+	return false
+}
+
+// withoutIgnored removes the IGNORE characters
+func (d *HunspellDictionary) withoutIgnored(word string) string {
+	if d.ignore == "" {
+		return word
+	}
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(d.ignore, r) {
+			return -1
+		}
+		return r
+	}, word)
+}
+
+// Check reports whether a word is correct. Capitalized and upper case words are also accepted
+// in lower case, as at the start of a sentence.
+func (d *HunspellDictionary) Check(word string) bool {
+	word = d.withoutIgnored(word)
+	if word == "" {
+		return true
+	}
+	for _, variant := range caseVariants(word) {
+		if d.isForbidden(variant) {
+			return false
+		}
+		if d.checkWord(variant) {
+			return true
+		}
+	}
+	return false
+}
+
+// caseVariants returns the word and the spellings it may have in the dictionary
+func caseVariants(word string) []string {
+	variants := []string{word}
+	first, size := utf8.DecodeRuneInString(word)
+	if !unicode.IsUpper(first) {
+		return variants
+	}
+	variants = append(variants, string(unicode.ToLower(first))+word[size:])
+	if strings.ToUpper(word) == word {
+		lower := strings.ToLower(word)
+		first, size = utf8.DecodeRuneInString(lower)
+		variants = append(variants, lower, string(unicode.ToUpper(first))+lower[size:])
+	}
+	return variants
+}
+
+// isForbidden reports whether a word is marked as forbidden
+func (d *HunspellDictionary) isForbidden(word string) bool {
+	for _, flags := range d.words[word] {
+		if d.hasFlag(flags, d.forbidden) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkWord checks a word on its own, derived through affixes or as a compound
+func (d *HunspellDictionary) checkWord(word string) bool {
+	standalone := func(flags string) bool {
+		return !d.hasFlag(flags, d.onlyInCompound) && !d.hasFlag(flags, d.forbidden)
+	}
+	for _, flags := range d.words[word] {
+		if standalone(flags) && !d.hasFlag(flags, d.needAffix) {
+			return true
+		}
+	}
+	if d.checkAffixed(word, standalone) {
+		return true
+	}
+	return d.checkCompound(word, 0)
+}
+
+// hasStem reports whether the dictionary has a stem with the flag whose flags satisfy accept
+func (d *HunspellDictionary) hasStem(stem, flag string, accept func(flags string) bool) bool {
+	for _, flags := range d.words[stem] {
+		if d.hasFlag(flags, flag) && accept(flags) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkAffixed reports whether a word is a stem with a suffix, a prefix or, for cross
+// products, both
+func (d *HunspellDictionary) checkAffixed(word string, accept func(flags string) bool) bool {
+	runes := []rune(word)
+
+	for k := 0; k < len(runes); k++ {
+		for _, rule := range d.suffixes[string(runes[len(runes)-k:])] {
+			stem := string(runes[:len(runes)-k]) + rule.Strip
+			if !rule.Condition.MatchString(stem) {
+				continue
+			}
+			if d.hasStem(stem, rule.Flag, accept) || d.checkContinuation(stem, rule.Flag, accept) {
+				return true
+			}
+			if rule.Cross && d.checkPrefixed(stem, func(flags string) bool {
+				return d.hasFlag(flags, rule.Flag) && accept(flags)
+			}, true) {
+				return true
+			}
+		}
+	}
+	return d.checkPrefixed(word, accept, false)
+}
+
+// checkContinuation reports whether a word is a stem with a suffix whose continuation class
+// allows the flag of a following suffix, e.g. walk + er + s
+func (d *HunspellDictionary) checkContinuation(word, flag string, accept func(flags string) bool) bool {
+	runes := []rune(word)
+	for k := 0; k <= len(runes); k++ {
+		for _, rule := range d.suffixes[string(runes[len(runes)-k:])] {
+			if !d.hasFlag(rule.Next, flag) {
+				continue
+			}
+			stem := string(runes[:len(runes)-k]) + rule.Strip
+			if rule.Condition.MatchString(stem) && d.hasStem(stem, rule.Flag, accept) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// checkPrefixed reports whether a word is a stem with a prefix, crossOnly limits the rules to
+// those that combine with suffixes
+func (d *HunspellDictionary) checkPrefixed(word string, accept func(flags string) bool, crossOnly bool) bool {
+	runes := []rune(word)
+	for k := 0; k < len(runes); k++ {
+		for _, rule := range d.prefixes[string(runes[:k])] {
+			if crossOnly && !rule.Cross {
+				continue
+			}
+			stem := rule.Strip + string(runes[k:])
+			if rule.Condition.MatchString(stem) && d.hasStem(stem, rule.Flag, accept) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// checkCompound reports whether a word consists of parts allowed in compounds. Later parts are
+// also looked up capitalized, like the nouns in German compounds.
+func (d *HunspellDictionary) checkCompound(word string, position int) bool {
+	if d.compound == "" && d.compoundBegin == "" {
+		return false
+	}
+	runes := []rune(word)
+
+	for i := d.compoundMin; i <= len(runes)-d.compoundMin; i++ {
+		part, rest := string(runes[:i]), string(runes[i:])
+		positionFlag := d.compoundMiddle
+		if position == 0 {
+			positionFlag = d.compoundBegin
+		}
+		if !d.compoundPart(part, positionFlag, position > 0) {
+			continue
+		}
+		if d.compoundPart(rest, d.compoundEnd, true) || d.checkCompound(rest, position+1) {
+			return true
+		}
+	}
+	return false
+}
+
+// compoundPart reports whether a part may appear at a position of a compound
+func (d *HunspellDictionary) compoundPart(part, positionFlag string, capitalize bool) bool {
+	accept := func(flags string) bool {
+		return (d.hasFlag(flags, d.compound) || d.hasFlag(flags, positionFlag)) && !d.hasFlag(flags, d.forbidden)
+	}
+	variants := []string{part}
+	if first, size := utf8.DecodeRuneInString(part); capitalize && unicode.IsLower(first) {
+		variants = append(variants, string(unicode.ToUpper(first))+part[size:])
+	}
+	for _, variant := range variants {
+		for _, flags := range d.words[variant] {
+			if accept(flags) {
+				return true
+			}
+		}
+		if d.checkAffixed(variant, accept) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func loadTestDictionary(t *testing.T) *HunspellDictionary {
+	t.Helper()
+	dictionary, err := LoadHunspellDictionary(filepath.Join("testdata", "hunspell", "en_TEST.aff"), filepath.Join("testdata", "hunspell", "en_TEST.dic"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dictionary
+}
+
+func TestHunspellCheck(t *testing.T) {
+	dictionary := loadTestDictionary(t)
+
+	tests := []struct {
+		name  string
+		words []string
+		want  bool
+	}{
+		{"stems", []string{"read", "walk", "party", "Read", "READ"}, true},
+		{"suffixes", []string{"reads", "parties", "writes"}, true},
+		{"suffix conditions", []string{"partys", "boxs", "readies"}, false},
+		{"AF aliases", []string{"unread", "walker"}, true},
+		{"cross products", []string{"unreads", "rewrite"}, true},
+		{"prefix without cross product", []string{"rewrites"}, false},
+		{"continuation classes", []string{"walkers"}, true},
+		{"suffixes without continuation", []string{"walkerer", "readser", "unwalk"}, false},
+		{"forbidden words", []string{"boxes", "Boxes"}, false},
+		{"NEEDAFFIX", []string{"scissor"}, false},
+		{"NEEDAFFIX with affix", []string{"scissors"}, true},
+		{"compounds", []string{"football", "ballfoot", "rainbow", "woodfoot", "Haustür"}, true},
+		{"compound positions", []string{"bowrain", "footrain", "footballs"}, false},
+		{"ONLYINCOMPOUND", []string{"wood"}, false},
+		{"COMPOUNDMIN", []string{"footbo"}, false},
+		{"unknown words", []string{"wlak", "raed"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, word := range tt.words {
+				if got := dictionary.Check(word); got != tt.want {
+					t.Errorf("Check(%q) = %v, want %v", word, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestFindDictionary(t *testing.T) {
+	directory := filepath.Join("testdata", "hunspell")
+	for _, lang := range []string{"en_TEST", "en"} {
+		if path, ok := findDictionary(directory, lang); !ok || filepath.Base(path) != "en_TEST.dic" {
+			t.Errorf("findDictionary(%q) = %q, %v", lang, path, ok)
+		}
+	}
+	if path, ok := findDictionary(directory, "de"); ok {
+		t.Errorf("findDictionary(de) = %q, want none", path)
+	}
+}
@@ -0,0 +1,535 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ICUKind is the kind of a parsed ICU MessageFormat node
+type ICUKind int
+
+const (
+	ICUText     ICUKind = iota // literal text, already unquoted
+	ICUArgument                // {name}, {name, number} or {name, plural, ...}
+	ICUPound                   // # inside a plural branch
+)
+
+// ICUNode is a single node of a parsed ICU MessageFormat message
+type ICUNode struct {
+	Kind    ICUKind
+	Text    string      // literal text of ICUText nodes
+	Raw     string      // source text of ICUArgument nodes including the braces
+	Arg     string      // argument name
+	Type    string      // "", number, date, time, spellout, ordinal, duration, plural, selectordinal or select
+	Style   string      // style of simple arguments, e.g. "integer" in {n, number, integer}
+	Offset  int         // offset of plural arguments
+	Options []ICUOption // branches of plural, selectordinal and select arguments in source order
+}
+
+// ICUOption is a single branch of a plural or select argument
+type ICUOption struct {
+	Selector string // plural category, =N or select keyword
+	Raw      string // source text between the braces
+	Message  []ICUNode
+}
+
+var icuSimpleTypes = []string{"number", "date", "time", "spellout", "ordinal", "duration"}
+var icuComplexTypes = []string{"plural", "selectordinal", "select"}
+
+// icuParser is a recursive descent parser following the ICU4J apostrophe rules:
+// a single apostrophe only starts quoting in front of a syntax character
+type icuParser struct {
+	s   string
+	pos int
+}
+
+// ParseICU parses an ICU MessageFormat message
+func ParseICU(message string) ([]ICUNode, error) {
+	p := &icuParser{s: message}
+	nodes, err := p.parseMessage(0, false)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.s) {
+		return nil, p.errorf("unmatched '}'")
+	}
+	return nodes, nil
+}
+
+func (p *icuParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("offset %d: %s", p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *icuParser) parseMessage(depth int, inPlural bool) ([]ICUNode, error) {
+	nodes := make([]ICUNode, 0)
+	var text strings.Builder
+
+	flushText := func() {
+		if text.Len() > 0 {
+			nodes = append(nodes, ICUNode{Kind: ICUText, Text: text.String()})
+			text.Reset()
+		}
+	}
+
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		switch {
+		case c == '\'':
+			p.parseQuoted(&text, inPlural)
+		case c == '{':
+			flushText()
+			node, err := p.parseArgument(depth, inPlural)
+			if err != nil {
+				return nil, err
+			}
+			nodes = append(nodes, node)
+		case c == '}':
+			if depth == 0 {
+				return nil, p.errorf("unmatched '}'")
+			}
+			flushText()
+			return nodes, nil
+		case c == '#' && inPlural:
+			flushText()
+			nodes = append(nodes, ICUNode{Kind: ICUPound})
+			p.pos++
+		default:
+			text.WriteByte(c)
+			p.pos++
+		}
+	}
+
+	if depth > 0 {
+		return nil, p.errorf("unclosed '{'")
+	}
+	flushText()
+	return nodes, nil
+}
+
+// parseQuoted handles an apostrophe at the current position
+func (p *icuParser) parseQuoted(text *strings.Builder, inPlural bool) {
+	p.pos++
+	if p.pos >= len(p.s) {
+		text.WriteByte('\'')
+		return
+	}
+
+	next := p.s[p.pos]
+	if next == '\'' {
+		text.WriteByte('\'')
+		p.pos++
+		return
+	}
+	if next != '{' && next != '}' && next != '|' && !(next == '#' && inPlural) {
+		text.WriteByte('\'')
+		return
+	}
+
+	// Quoted literal text up to the next single apostrophe
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		p.pos++
+		if c == '\'' {
+			if p.pos < len(p.s) && p.s[p.pos] == '\'' {
+				text.WriteByte('\'')
+				p.pos++
+				continue
+			}
+			return
+		}
+		text.WriteByte(c)
+	}
+}
+
+func (p *icuParser) skipSpace() {
+	for p.pos < len(p.s) && unicode.IsSpace(rune(p.s[p.pos])) {
+		p.pos++
+	}
+}
+
+// readToken reads up to the next space or syntax character
+func (p *icuParser) readToken() string {
+	start := p.pos
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		if c == '{' || c == '}' || c == ',' || c == '\'' || c == '#' || unicode.IsSpace(rune(c)) {
+			break
+		}
+		p.pos++
+	}
+	return p.s[start:p.pos]
+}
+
+func (p *icuParser) parseArgument(depth int, inPlural bool) (ICUNode, error) {
+	start := p.pos
+	node := ICUNode{Kind: ICUArgument}
+	p.pos++ // {
+
+	p.skipSpace()
+	if p.pos >= len(p.s) {
+		return node, p.errorf("unclosed '{'")
+	}
+	node.Arg = p.readToken()
+	if node.Arg == "" {
+		return node, p.errorf("missing argument name")
+	}
+	p.skipSpace()
+
+	if p.pos >= len(p.s) {
+		return node, p.errorf("unclosed argument {%s", node.Arg)
+	}
+	if p.s[p.pos] == '}' {
+		p.pos++
+		node.Raw = p.s[start:p.pos]
+		return node, nil
+	}
+	if p.s[p.pos] != ',' {
+		return node, p.errorf("expected ',' or '}' after argument %s", node.Arg)
+	}
+	p.pos++
+
+	p.skipSpace()
+	node.Type = p.readToken()
+	p.skipSpace()
+
+	switch {
+	case slices.Contains(icuSimpleTypes, node.Type):
+		if p.pos < len(p.s) && p.s[p.pos] == ',' {
+			p.pos++
+			styleStart := p.pos
+			end := matchingBrace(p.s, start)
+			if end < 0 {
+				return node, p.errorf("unclosed argument {%s", node.Arg)
+			}
+			node.Style = strings.TrimSpace(p.s[styleStart:end])
+			p.pos = end
+		}
+		if p.pos >= len(p.s) || p.s[p.pos] != '}' {
+			return node, p.errorf("expected '}' after argument %s", node.Arg)
+		}
+		p.pos++
+	case slices.Contains(icuComplexTypes, node.Type):
+		if p.pos >= len(p.s) || p.s[p.pos] != ',' {
+			return node, p.errorf("%s argument %s has no branches", node.Type, node.Arg)
+		}
+		p.pos++
+		if err := p.parseOptions(&node, depth, inPlural); err != nil {
+			return node, err
+		}
+	case node.Type == "":
+		return node, p.errorf("missing type of argument %s", node.Arg)
+	default:
+		return node, p.errorf("unknown type %q of argument %s", node.Type, node.Arg)
+	}
+
+	node.Raw = p.s[start:p.pos]
+	return node, nil
+}
+
+var icuExplicitSelector = regexp.MustCompile(`^=\d+$`)
+
+func (p *icuParser) parseOptions(node *ICUNode, depth int, inPlural bool) error {
+	isPlural := node.Type != "select"
+	seen := make(map[string]bool)
+
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.s) {
+			return p.errorf("unclosed %s argument %s", node.Type, node.Arg)
+		}
+		if p.s[p.pos] == '}' {
+			p.pos++
+			break
+		}
+
+		selector := p.readToken()
+		if isPlural && strings.HasPrefix(selector, "offset:") && len(node.Options) == 0 {
+			offset, err := strconv.Atoi(strings.TrimPrefix(selector, "offset:"))
+			if err != nil {
+				return p.errorf("invalid offset %q", selector)
+			}
+			node.Offset = offset
+			continue
+		}
+		if selector == "" {
+			return p.errorf("missing selector in %s argument %s", node.Type, node.Arg)
+		}
+		if isPlural && !slices.Contains(pluralCategoryOrder, selector) && !icuExplicitSelector.MatchString(selector) {
+			return p.errorf("invalid plural selector %q in argument %s", selector, node.Arg)
+		}
+		if seen[selector] {
+			return p.errorf("duplicate selector %q in argument %s", selector, node.Arg)
+		}
+		seen[selector] = true
+
+		p.skipSpace()
+		if p.pos >= len(p.s) || p.s[p.pos] != '{' {
+			return p.errorf("expected '{' after selector %q", selector)
+		}
+		p.pos++
+		messageStart := p.pos
+		message, err := p.parseMessage(depth+1, inPlural || isPlural)
+		if err != nil {
+			return err
+		}
+		option := ICUOption{Selector: selector, Raw: p.s[messageStart:p.pos], Message: message}
+		p.pos++ // }
+		node.Options = append(node.Options, option)
+	}
+
+	if !seen["other"] {
+		return p.errorf("%s argument %s has no 'other' branch", node.Type, node.Arg)
+	}
+	return nil
+}
+
+// matchingBrace returns the index of the brace closing the one at start, or -1.
+// Apostrophe quoting is not considered.
+func matchingBrace(s string, start int) int {
+	depth := 0
+	for i := start; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// ICUArguments returns all arguments of a message, including nested ones, mapped to their type
+func ICUArguments(nodes []ICUNode) map[string]string {
+	result := make(map[string]string)
+	var walk func(nodes []ICUNode)
+	walk = func(nodes []ICUNode) {
+		for _, n := range nodes {
+			if n.Kind != ICUArgument {
+				continue
+			}
+			result[n.Arg] = n.Type
+			for _, o := range n.Options {
+				walk(o.Message)
+			}
+		}
+	}
+	walk(nodes)
+	return result
+}
+
+// icuPluralNodes returns all plural arguments of a message, including nested ones
+func icuPluralNodes(nodes []ICUNode) []ICUNode {
+	result := make([]ICUNode, 0)
+	for _, n := range nodes {
+		if n.Kind != ICUArgument {
+			continue
+		}
+		if n.Type == "plural" {
+			result = append(result, n)
+		}
+		for _, o := range n.Options {
+			result = append(result, icuPluralNodes(o.Message)...)
+		}
+	}
+	return result
+}
+
+// hasICUStructure reports whether a message contains plural or select arguments
+func hasICUStructure(nodes []ICUNode) bool {
+	for _, n := range nodes {
+		if n.Kind == ICUArgument && len(n.Options) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateICU checks the syntax of a translation and compares its arguments with the English
+// source. Missing plural categories of the language are reported as warnings.
+func ValidateICU(source, translation, lang string) (errors []string, warnings []string) {
+	sourceNodes, err := ParseICU(source)
+	if err != nil {
+		return nil, nil // the source is checked on its own row
+	}
+	nodes, err := ParseICU(translation)
+	if err != nil {
+		return []string{fmt.Sprintf("invalid ICU syntax: %v", err)}, nil
+	}
+
+	sourceArgs := ICUArguments(sourceNodes)
+	args := ICUArguments(nodes)
+	for _, name := range sortedKeys(sourceArgs) {
+		argType, ok := args[name]
+		if !ok {
+			errors = append(errors, fmt.Sprintf("missing argument {%s}", name))
+		} else if argType != sourceArgs[name] {
+			errors = append(errors, fmt.Sprintf("argument {%s} is %s, expected %s", name, icuTypeName(argType), icuTypeName(sourceArgs[name])))
+		}
+	}
+	for _, name := range sortedKeys(args) {
+		if _, ok := sourceArgs[name]; !ok {
+			errors = append(errors, fmt.Sprintf("unknown argument {%s}", name))
+		}
+	}
+
+	required := pluralCategories(lang)
+	for _, plural := range icuPluralNodes(nodes) {
+		present := make(map[string]bool)
+		for _, o := range plural.Options {
+			present[o.Selector] = true
+		}
+		for _, category := range required {
+			if !present[category] {
+				warnings = append(warnings, fmt.Sprintf("plural {%s} has no '%s' branch needed by %s", plural.Arg, category, lang))
+			}
+		}
+		for _, o := range plural.Options {
+			if slices.Contains(pluralCategoryOrder, o.Selector) && !slices.Contains(required, o.Selector) {
+				warnings = append(warnings, fmt.Sprintf("plural {%s} has a '%s' branch that %s never uses", plural.Arg, o.Selector, lang))
+			}
+		}
+	}
+	return errors, warnings
+}
+
+func icuTypeName(argType string) string {
+	if argType == "" {
+		return "a simple argument"
+	}
+	return argType
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ICUPluralToNative converts a message consisting of a single plural argument into
+// printf style singular and plural forms, "#" becomes "%d"
+func ICUPluralToNative(message string) (arg, one, other string, ok bool) {
+	nodes, err := ParseICU(strings.TrimSpace(message))
+	if err != nil || len(nodes) != 1 || nodes[0].Kind != ICUArgument || nodes[0].Type != "plural" {
+		return "", "", "", false
+	}
+
+	forms := make(map[string]string)
+	for _, o := range nodes[0].Options {
+		forms[o.Selector] = icuToNative(o.Message)
+	}
+	one, hasOne := forms["one"]
+	if !hasOne {
+		one = forms["=1"]
+	}
+	return nodes[0].Arg, one, forms["other"], true
+}
+
+// icuToNative renders parsed nodes as plain text with "#" replaced by "%d"
+func icuToNative(nodes []ICUNode) string {
+	var sb strings.Builder
+	for _, n := range nodes {
+		switch n.Kind {
+		case ICUText:
+			sb.WriteString(n.Text)
+		case ICUPound:
+			sb.WriteString("%d")
+		case ICUArgument:
+			sb.WriteString(n.Raw)
+		}
+	}
+	return sb.String()
+}
+
+var nativeNumberPlaceholder = regexp.MustCompile(`%(?:\d+\$)?\d*d`)
+
+// nativeToken matches the parts of a native text that are kept as they are: printf
+// placeholders and simple {name} arguments
+var nativeToken = regexp.MustCompile(`%(?:\d+\$)?\d*d|\{[A-Za-z_][A-Za-z0-9_]*\}`)
+
+// NativePluralToICU builds a plural argument with one branch per plural category of the
+// language from printf style forms. The number placeholder "%d" becomes "#", or {arg}
+// if numberAsArgument is set (as Flutter expects).
+func NativePluralToICU(arg, lang, one, other string, numberAsArgument bool) string {
+	number := "#"
+	if numberAsArgument {
+		number = "{" + arg + "}"
+	}
+
+	var sb strings.Builder
+	sb.WriteString("{" + arg + ", plural,")
+	for _, category := range pluralCategories(lang) {
+		form := pluralFormForCategory(category, one, other)
+		if form == "" {
+			continue
+		}
+		sb.WriteString(" " + category + " {" + nativeToICU(form, number) + "}")
+	}
+	sb.WriteString("}")
+	return sb.String()
+}
+
+// nativeToICU quotes ICU syntax characters of a plain text and replaces the number placeholder
+func nativeToICU(text, number string) string {
+	var sb strings.Builder
+	last := 0
+	for _, loc := range nativeToken.FindAllStringIndex(text, -1) {
+		sb.WriteString(quoteICU(text[last:loc[0]]))
+		token := text[loc[0]:loc[1]]
+		if nativeNumberPlaceholder.MatchString(token) {
+			token = number
+		}
+		sb.WriteString(token)
+		last = loc[1]
+	}
+	sb.WriteString(quoteICU(text[last:]))
+	return sb.String()
+}
+
+// quoteICU quotes all ICU syntax characters of a literal text
+func quoteICU(text string) string {
+	var sb strings.Builder
+	for _, r := range text {
+		switch r {
+		case '\'':
+			sb.WriteString("''")
+		case '{', '}', '#':
+			sb.WriteString("'" + string(r) + "'")
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// icuPromptHint describes the ICU structure of a text for LLM prompts, empty for plain texts
+func icuPromptHint(text string) string {
+	nodes, err := ParseICU(text)
+	if err != nil {
+		return ""
+	}
+	args := ICUArguments(nodes)
+	if len(args) == 0 {
+		return ""
+	}
+
+	names := make([]string, 0, len(args))
+	for _, name := range sortedKeys(args) {
+		names = append(names, "{"+name+"}")
+	}
+	hint := fmt.Sprintf("The text is an ICU MessageFormat message. Keep the arguments %s unchanged.", strings.Join(names, ", "))
+	if hasICUStructure(nodes) {
+		hint += " Keep the plural/select keywords and branch selectors unchanged and only change the text inside the branches."
+	}
+	return hint
+}
@@ -23,6 +23,34 @@ type XCStringsEntry struct {
 
 // XCStringsLocalization represents a localization in an .xcstrings file
 type XCStringsLocalization struct {
+	StringUnit XCStringsUnit        `json:"stringUnit"`
+	Variations *XCStringsVariations `json:"variations,omitempty"`
+}
+
+// MarshalJSON writes either the string unit or the variations, as Xcode does
+func (l XCStringsLocalization) MarshalJSON() ([]byte, error) {
+	if l.Variations != nil {
+		return json.Marshal(struct {
+			Variations *XCStringsVariations `json:"variations"`
+		}{l.Variations})
+	}
+	return json.Marshal(struct {
+		StringUnit XCStringsUnit `json:"stringUnit"`
+	}{l.StringUnit})
+}
+
+// translated reports whether a string unit has a value in the "translated" state
+func (u XCStringsUnit) translated() bool {
+	return u.State == "translated" && u.Value != ""
+}
+
+// XCStringsVariations represents the plural variations of a localization in an .xcstrings file
+type XCStringsVariations struct {
+	Plural map[string]XCStringsVariation `json:"plural,omitempty"`
+}
+
+// XCStringsVariation represents a single plural category of a localization
+type XCStringsVariation struct {
 	StringUnit XCStringsUnit `json:"stringUnit"`
 }
 
@@ -69,8 +97,36 @@ func ImportFromXCStrings(tm *Translations, baseDirectory string) error {
 
 		// Process localizations
 		for lang, localization := range entry.Localizations {
+			if isPseudoLocale(lang) {
+				continue
+			}
+
+			// Native plural variations map onto the singular/plural rows
+			if localization.Variations != nil && len(localization.Variations.Plural) > 0 {
+				plural := localization.Variations.Plural
+				if !plural["other"].StringUnit.translated() {
+					continue
+				}
+				one := ""
+				if plural["one"].StringUnit.translated() {
+					one = plural["one"].StringUnit.Value
+				}
+				tm.SetPluralTranslation("ios", key, lang, one, plural["other"].StringUnit.Value, comment)
+				tm.EnsureLanguage(lang)
+				importCount++
+				continue
+			}
+
+			// ICU plurals are converted to the native singular/plural rows as well
+			if _, one, other, ok := ICUPluralToNative(localization.StringUnit.Value); ok && localization.StringUnit.translated() {
+				tm.SetPluralTranslation("ios", key, lang, one, other, comment)
+				tm.EnsureLanguage(lang)
+				importCount++
+				continue
+			}
+
 			// Only add translations that are in "translated" state and have a value
-			if localization.StringUnit.State == "translated" && localization.StringUnit.Value != "" {
+			if localization.StringUnit.translated() {
 				// Check if it's a plural key
 				if isPluralKey(key) {
 					baseKey := normalizeKey(key)
@@ -142,54 +198,28 @@ func ExportToXCStrings(tm *Translations, baseDirectory string) error {
 				continue
 			}
 
-			// Create singular entry
-			if hasNonEmptyValues(pluralValues.One) {
-				singularKey := baseKey + ".singular"
-				singularEntry := XCStringsEntry{
-					Comment:       trans.Comment + " (singular)",
-					Localizations: make(map[string]XCStringsLocalization),
-				}
-
-				// Add localizations for singular
-				for lang, value := range pluralValues.One {
-					if value != "" {
-						singularEntry.Localizations[lang] = XCStringsLocalization{
-							StringUnit: XCStringsUnit{
-								State: "translated",
-								Value: value,
-							},
-						}
-					}
-				}
-
-				if len(singularEntry.Localizations) > 0 {
-					xcstrings.Strings[singularKey] = singularEntry
-				}
+			// The pair is written under its base key with plural variations, like Xcode does
+			entry := XCStringsEntry{
+				Comment:       trans.Comment,
+				Localizations: make(map[string]XCStringsLocalization),
 			}
-
-			// Create plural entry
-			if hasNonEmptyValues(pluralValues.Other) {
-				pluralKey := baseKey + ".plural"
-				pluralEntry := XCStringsEntry{
-					Comment:       trans.Comment + " (plural)",
-					Localizations: make(map[string]XCStringsLocalization),
+			for _, lang := range tm.Languages {
+				one, other := pluralValues.One[lang], pluralValues.Other[lang]
+				if other == "" {
+					other = one
 				}
-
-				// Add localizations for plural
-				for lang, value := range pluralValues.Other {
-					if value != "" {
-						pluralEntry.Localizations[lang] = XCStringsLocalization{
-							StringUnit: XCStringsUnit{
-								State: "translated",
-								Value: value,
-							},
-						}
-					}
+				if other == "" {
+					continue
 				}
-
-				if len(pluralEntry.Localizations) > 0 {
-					xcstrings.Strings[pluralKey] = pluralEntry
+				// A missing "one" falls back to "other" in the app
+				variations := map[string]XCStringsVariation{"other": {StringUnit: XCStringsUnit{State: "translated", Value: other}}}
+				if one != "" {
+					variations["one"] = XCStringsVariation{StringUnit: XCStringsUnit{State: "translated", Value: one}}
 				}
+				entry.Localizations[lang] = XCStringsLocalization{Variations: &XCStringsVariations{Plural: variations}}
+			}
+			if len(entry.Localizations) > 0 {
+				xcstrings.Strings[baseKey] = entry
 			}
 
 			processedPlurals[baseKey] = true
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestXCStringsPluralRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	tm := NewTranslations("")
+	tm.Languages = []string{"de", "en"}
+	tm.SetPluralTranslation("ios", "items", "en", "%d item", "%d items", "Number of items")
+	tm.SetPluralTranslation("ios", "items", "de", "", "%d Elemente", "")
+	tm.SetTranslation("ios", "ok", "en", "OK", "")
+
+	if err := ExportToXCStrings(tm, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	imported := NewTranslations("")
+	if err := ImportFromXCStrings(imported, dir); err != nil {
+		t.Fatal(err)
+	}
+	for _, row := range tm.Translations {
+		got := imported.GetRow("ios", row.Key)
+		if got == nil {
+			t.Errorf("ios:%s missing after import", row.Key)
+			continue
+		}
+		for _, lang := range tm.Languages {
+			if got.Values[lang] != row.Values[lang] {
+				t.Errorf("ios:%s [%s] = %q, want %q", row.Key, lang, got.Values[lang], row.Values[lang])
+			}
+		}
+	}
+	if len(imported.Translations) != len(tm.Translations) {
+		t.Errorf("imported %d rows, want %d", len(imported.Translations), len(tm.Translations))
+	}
+}
+
+func TestImportFromXCStringsSkipsUntranslated(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "ios", "Zeitkapsl", "Supporting Files")
+	if err := os.MkdirAll(path, 0755); err != nil {
+		t.Fatal(err)
+	}
+	content := `{"sourceLanguage": "en", "strings": {"items": {"localizations": {
+		"de": {"stringUnit": {"state": "new", "value": "{count, plural, one {# Element} other {# Elemente}}"}},
+		"fr": {"variations": {"plural": {"one": {"stringUnit": {"state": "needs_review", "value": "# élément"}}, "other": {"stringUnit": {"state": "needs_review", "value": "# éléments"}}}}},
+		"en": {"variations": {"plural": {"one": {"stringUnit": {"state": "translated", "value": "%d item"}}, "other": {"stringUnit": {"state": "translated", "value": "%d items"}}}}}
+	}}}}`
+	if err := os.WriteFile(filepath.Join(path, "Localizable.xcstrings"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tm := NewTranslations("")
+	if err := ImportFromXCStrings(tm, dir); err != nil {
+		t.Fatal(err)
+	}
+	plural := tm.GetPlural("ios", "items")
+	if plural == nil || plural.One["en"] != "%d item" || plural.Other["en"] != "%d items" {
+		t.Fatalf("plural = %+v, want the English forms", plural)
+	}
+	for _, lang := range []string{"de", "fr"} {
+		if plural.One[lang] != "" || plural.Other[lang] != "" {
+			t.Errorf("untranslated %s values imported: %q, %q", lang, plural.One[lang], plural.Other[lang])
+		}
+	}
+}
@@ -7,9 +7,94 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"sort"
 	"strings"
 )
 
+// DefaultModuleConfigFile is the default filename for the per-module options
+const DefaultModuleConfigFile = "modules.json"
+
+// JSONOptions configures how the files of a JSON module are written
+type JSONOptions struct {
+	// Nested writes dotted keys as nested objects ({"a": {"b": "..."}}) instead of a flat object
+	Nested bool `json:"nested"`
+	// PluralStyle selects how plurals are written (legacy|i18next|icu), legacy if empty
+	PluralStyle string `json:"plural_style,omitempty"`
+	// PluralArgument is the argument name of ICU plurals, "count" if empty
+	PluralArgument string `json:"plural_argument,omitempty"`
+	// KeyOrder selects the key order of written files (alphabetical|source), alphabetical if empty
+	KeyOrder string `json:"key_order,omitempty"`
+	// RemoveOrphans deletes language files that have no translations instead of warning about them
+	RemoveOrphans bool `json:"remove_orphans"`
+}
+
+// ModuleConfig holds the options per app: the JSON options, the Go accessor options and
+// generate_on_export. Options missing in the file keep the module's defaults.
+type ModuleConfig map[string]json.RawMessage
+
+// moduleGenerateOptions are the options of when a module's accessors are generated
+type moduleGenerateOptions struct {
+	GenerateOnExport bool `json:"generate_on_export"`
+}
+
+// LoadModuleConfig loads the module configuration, a missing file yields an empty configuration
+func LoadModuleConfig(filename string) (ModuleConfig, error) {
+	if filename == "" {
+		filename = DefaultModuleConfigFile
+	}
+
+	config := make(ModuleConfig)
+	data, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return config, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading module configuration %s: %v", filename, err)
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("error parsing module configuration %s: %v", filename, err)
+	}
+
+	for app := range config {
+		opts, err := config.JSONOptions(app, JSONOptions{})
+		if err == nil {
+			err = config.Options(app, &GoAccessorOptions{})
+		}
+		if err == nil {
+			err = config.Options(app, &moduleGenerateOptions{})
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error in module configuration %s: %v", filename, err)
+		}
+		if opts.PluralStyle != "" && opts.PluralStyle != PluralStyleLegacy && opts.PluralStyle != PluralStyleI18next && opts.PluralStyle != PluralStyleICU {
+			return nil, fmt.Errorf("error in module configuration %s: unknown plural_style %q for %s", filename, opts.PluralStyle, app)
+		}
+		if opts.KeyOrder != "" && opts.KeyOrder != KeyOrderAlphabetical && opts.KeyOrder != KeyOrderSource {
+			return nil, fmt.Errorf("error in module configuration %s: unknown key_order %q for %s", filename, opts.KeyOrder, app)
+		}
+	}
+	return config, nil
+}
+
+// JSONOptions returns the configured options of an app on top of its defaults
+func (c ModuleConfig) JSONOptions(app string, defaults JSONOptions) (JSONOptions, error) {
+	opts := defaults
+	if err := c.Options(app, &opts); err != nil {
+		return defaults, err
+	}
+	return opts, nil
+}
+
+// Options decodes the configured options of an app into target, which holds the defaults
+func (c ModuleConfig) Options(app string, target interface{}) error {
+	if raw, ok := c[app]; ok {
+		if err := json.Unmarshal(raw, target); err != nil {
+			return fmt.Errorf("invalid options for %s: %v", app, err)
+		}
+	}
+	return nil
+}
+
 func ImportFromJSON(tm *Translations, app, baseDirectory string) error {
 	// Check if the primary path exists
 	if _, err := os.Stat(baseDirectory); os.IsNotExist(err) {
@@ -23,8 +108,12 @@ func ImportFromJSON(tm *Translations, app, baseDirectory string) error {
 				return nil
 
 			}
-			fmt.Println("processing: " + path)
 			lang := parts[0]
+			if isPseudoLocale(lang) {
+				fmt.Println("skipping pseudo locale: " + path)
+				return nil
+			}
+			fmt.Println("processing: " + path)
 			return importFromJavaScriptFile(tm, app, lang, path)
 		}
 		return nil
@@ -39,19 +128,122 @@ func importFromJavaScriptFile(tm *Translations, app, lang, filePath string) erro
 		return fmt.Errorf("error reading %s: %v", filePath, err)
 	}
 
-	var parseData map[string]string
+	var parseData map[string]interface{}
 
 	err = json.NewDecoder(bytes.NewReader(data)).Decode(&parseData)
 	if err != nil {
 		return fmt.Errorf("failed to parse %s: %w", filePath, err)
 	}
-	for k, v := range parseData {
+
+	flat := make(map[string]string)
+	for _, problem := range flattenJSON("", parseData, flat) {
+		fmt.Printf("Warning: %s: %s\n", filePath, problem)
+	}
+	singles, plurals := foldJSONPlurals(flat, filePath)
+	for k, v := range singles {
 		tm.SetTranslation(app, k, lang, v, "")
 	}
+	for k, v := range plurals {
+		tm.SetPluralTranslation(app, k, lang, v[0], v[1], "")
+	}
 	return nil
 }
 
-func ExportToJson(tm *Translations, app, baseDirectory string) error {
+// foldJSONPlurals detects i18next (key_one/key_other) and ICU plurals in a flat file and
+// returns them as singular/plural pairs. All other keys, including legacy
+// key.singular/key.plural pairs, are returned unchanged.
+func foldJSONPlurals(flat map[string]string, filePath string) (map[string]string, map[string][2]string) {
+	singles := make(map[string]string)
+	plurals := make(map[string][2]string)
+
+	groups := make(map[string]map[string]string)
+	for key, value := range flat {
+		if base, category, ok := splitI18nextPluralKey(key); ok {
+			if groups[base] == nil {
+				groups[base] = make(map[string]string)
+			}
+			groups[base][category] = value
+		}
+	}
+
+	for key, value := range flat {
+		if base, category, ok := splitI18nextPluralKey(key); ok {
+			group := groups[base]
+			if _, hasOther := group["other"]; hasOther && len(group) > 1 {
+				if category == "one" || category == "other" {
+					continue
+				}
+				fmt.Printf("Warning: %s: dropping %q, only one and other forms are kept\n", filePath, key)
+				continue
+			}
+		}
+
+		if _, one, other, ok := ICUPluralToNative(value); ok {
+			plurals[key] = [2]string{one, other}
+			continue
+		}
+
+		singles[key] = value
+	}
+
+	for base, group := range groups {
+		if _, hasOther := group["other"]; hasOther && len(group) > 1 {
+			plurals[base] = [2]string{group["one"], group["other"]}
+		}
+	}
+	return singles, plurals
+}
+
+// flattenJSON flattens nested objects into dotted keys and returns all problems found,
+// such as non-string values or keys that are defined both nested and dotted
+func flattenJSON(prefix string, object map[string]interface{}, result map[string]string) []string {
+	problems := make([]string, 0)
+	keys := make([]string, 0, len(object))
+	for k := range object {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		v := object[k]
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+
+		switch value := v.(type) {
+		case string:
+			if _, exists := result[key]; exists {
+				problems = append(problems, fmt.Sprintf("key %q is defined more than once", key))
+				continue
+			}
+			result[key] = value
+		case map[string]interface{}:
+			problems = append(problems, flattenJSON(key, value, result)...)
+		default:
+			problems = append(problems, fmt.Sprintf("skipping key %q with unsupported value %v", key, value))
+		}
+	}
+	return problems
+}
+
+// findNestingConflicts returns all keys that are a leaf and a prefix of another key at the same time
+func findNestingConflicts(flat map[string]string) []string {
+	conflicts := make([]string, 0)
+	for key := range flat {
+		parts := strings.Split(key, ".")
+		for i := 1; i < len(parts); i++ {
+			prefix := strings.Join(parts[:i], ".")
+			if _, ok := flat[prefix]; ok {
+				conflicts = append(conflicts, fmt.Sprintf("%q is a value and the parent of %q", prefix, key))
+			}
+		}
+	}
+	sort.Strings(conflicts)
+	return conflicts
+}
+
+func ExportToJson(tm *Translations, app, baseDirectory string, opts JSONOptions) error {
 	tm.Sort()
 
 	if err := os.MkdirAll(baseDirectory, 0755); err != nil {
@@ -60,33 +252,98 @@ func ExportToJson(tm *Translations, app, baseDirectory string) error {
 
 	translations := tm.GetTranslationsForApp(app)
 
+	written := make(map[string]bool)
+
 	// Export each language as a separate JSON file
 	for _, lang := range tm.Languages {
-		object := make(map[string]string)
+		flat := make(map[string]string)
+		processedPlurals := make(map[string]bool)
 		containsValue := false
 		for _, t := range translations {
 			if v, ok := t.Values[lang]; ok && v != "" {
-				object[t.Key] = v
 				containsValue = true
+				if !t.IsPlural() || opts.PluralStyle == "" || opts.PluralStyle == PluralStyleLegacy {
+					flat[t.Key] = v
+					continue
+				}
+
+				baseKey := t.GetSingularKey()
+				if processedPlurals[baseKey] {
+					continue
+				}
+				processedPlurals[baseKey] = true
+				pluralValues := tm.GetPlural(app, baseKey)
+				if pluralValues == nil {
+					flat[t.Key] = v
+					continue
+				}
+				if err := addJSONPlural(flat, baseKey, lang, pluralValues.One[lang], pluralValues.Other[lang], opts); err != nil {
+					return err
+				}
 			}
 		}
 
-		if containsValue {
-			targetPath := path.Join(baseDirectory, lang+".json")
-			f, err := os.Create(targetPath)
-			if err != nil {
-				return err
-			}
-			defer f.Close()
-			enc := json.NewEncoder(f)
-			enc.SetIndent("", "  ")
-			err = enc.Encode(object)
-			if err != nil {
-				return err
+		if !containsValue {
+			continue
+		}
+
+		if opts.Nested {
+			if conflicts := findNestingConflicts(flat); len(conflicts) > 0 {
+				return fmt.Errorf("cannot write nested %s.json, keys conflict:\n  %s", lang, strings.Join(conflicts, "\n  "))
 			}
+		}
+
+		targetPath := path.Join(baseDirectory, lang+".json")
+		keys := make([]string, 0, len(flat))
+		for k := range flat {
+			keys = append(keys, k)
+		}
+		keys = orderKeys(keys, keyOrderFor(opts.KeyOrder, targetPath, path.Join(baseDirectory, "en.json")))
+
+		changed, err := writeJSONFile(targetPath, buildOrderedJSON(flat, keys, opts.Nested))
+		if err != nil {
+			return err
+		}
+		written[targetPath] = true
+		if changed {
 			fmt.Println("Create translation: " + targetPath)
 		}
 	}
+
+	err := removeStalePseudoLocales(tm, func(lang string) []string {
+		return []string{path.Join(baseDirectory, lang+".json")}
+	})
+	if err != nil {
+		return err
+	}
+	if err := handleOrphanedFiles(baseDirectory, localeFilePattern("", ".json"), written, opts.RemoveOrphans); err != nil {
+		return err
+	}
 	fmt.Printf("Exported web translations to separate JSON files in %s\n", baseDirectory)
 	return nil
 }
+
+// addJSONPlural writes a singular/plural pair in the configured plural style
+func addJSONPlural(flat map[string]string, key, lang, one, other string, opts JSONOptions) error {
+	if other == "" {
+		other = one
+	}
+
+	switch opts.PluralStyle {
+	case PluralStyleI18next:
+		for _, category := range pluralCategories(lang) {
+			if form := pluralFormForCategory(category, one, other); form != "" {
+				flat[key+"_"+category] = form
+			}
+		}
+	case PluralStyleICU:
+		arg := opts.PluralArgument
+		if arg == "" {
+			arg = "count"
+		}
+		flat[key] = NativePluralToICU(arg, lang, one, other, false)
+	default:
+		return fmt.Errorf("unknown plural style: %s. Use '%s', '%s' or '%s'", opts.PluralStyle, PluralStyleLegacy, PluralStyleI18next, PluralStyleICU)
+	}
+	return nil
+}
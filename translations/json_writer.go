@@ -0,0 +1,255 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Key orders supported by the JSON writers
+const (
+	KeyOrderAlphabetical = "alphabetical"
+	KeyOrderSource       = "source" // order of the existing file, new keys are appended alphabetically
+)
+
+// orderedObject is a JSON object that keeps the order in which keys were set
+type orderedObject struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func newOrderedObject() *orderedObject {
+	return &orderedObject{values: make(map[string]interface{})}
+}
+
+// Set adds or replaces a value, new keys are appended
+func (o *orderedObject) Set(key string, value interface{}) {
+	if _, ok := o.values[key]; !ok {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = value
+}
+
+func (o *orderedObject) Get(key string) (interface{}, bool) {
+	value, ok := o.values[key]
+	return value, ok
+}
+
+func (o *orderedObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, err := marshalNoEscape(key)
+		if err != nil {
+			return nil, err
+		}
+		v, err := marshalNoEscape(o.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// marshalNoEscape encodes a value without escaping &, < and >
+func marshalNoEscape(value interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(value); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
+
+// writeJSONFile writes an indented JSON document with a trailing newline and without HTML
+// escaping. The file is left untouched if its content would not change.
+func writeJSONFile(targetPath string, object interface{}) (bool, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(object); err != nil {
+		return false, fmt.Errorf("error generating %s: %v", targetPath, err)
+	}
+
+	if existing, err := os.ReadFile(targetPath); err == nil && bytes.Equal(existing, buf.Bytes()) {
+		return false, nil
+	}
+	if err := os.WriteFile(targetPath, buf.Bytes(), 0644); err != nil {
+		return false, fmt.Errorf("error writing %s: %v", targetPath, err)
+	}
+	return true, nil
+}
+
+// readJSONKeyOrder returns the keys of an existing JSON file in file order, nested keys
+// are returned as dotted keys. A missing or invalid file yields no keys.
+func readJSONKeyOrder(filePath string) []string {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil
+	}
+
+	keys := make([]string, 0)
+	dec := json.NewDecoder(bytes.NewReader(data))
+	var walk func(prefix string) error
+	walk = func(prefix string) error {
+		// The opening brace has been consumed
+		for dec.More() {
+			token, err := dec.Token()
+			if err != nil {
+				return err
+			}
+			key, _ := token.(string)
+			if prefix != "" {
+				key = prefix + "." + key
+			}
+
+			next, err := dec.Token()
+			if err != nil {
+				return err
+			}
+			switch next {
+			case json.Delim('{'):
+				if err := walk(key); err != nil {
+					return err
+				}
+			case json.Delim('['):
+				for dec.More() {
+					var skip interface{}
+					if err := dec.Decode(&skip); err != nil {
+						return err
+					}
+				}
+				if _, err := dec.Token(); err != nil {
+					return err
+				}
+				keys = append(keys, key)
+			default:
+				keys = append(keys, key)
+			}
+		}
+		_, err := dec.Token() // closing brace
+		return err
+	}
+
+	if token, err := dec.Token(); err != nil || token != json.Delim('{') {
+		return nil
+	}
+	if err := walk(""); err != nil {
+		return nil
+	}
+	return keys
+}
+
+// orderKeys sorts keys by their position in the given order, keys that are not part of
+// the order follow alphabetically
+func orderKeys(keys []string, order []string) []string {
+	position := make(map[string]int, len(order))
+	for i, key := range order {
+		if _, ok := position[key]; !ok {
+			position[key] = i
+		}
+	}
+
+	result := append([]string(nil), keys...)
+	sort.SliceStable(result, func(i, j int) bool {
+		pi, oki := position[result[i]]
+		pj, okj := position[result[j]]
+		switch {
+		case oki && okj:
+			return pi < pj
+		case oki != okj:
+			return oki
+		default:
+			return result[i] < result[j]
+		}
+	})
+	return result
+}
+
+// keyOrderFor returns the reference order for a file: the file itself followed by the
+// source language file, or nothing for alphabetical order
+func keyOrderFor(keyOrder, targetPath, sourcePath string) []string {
+	if keyOrder != KeyOrderSource {
+		return nil
+	}
+	order := readJSONKeyOrder(targetPath)
+	if sourcePath != targetPath {
+		order = append(order, readJSONKeyOrder(sourcePath)...)
+	}
+	return order
+}
+
+// localePattern matches a locale like de, de_AT, zh_Hant_TW or es_419
+const localePattern = `[a-z]{2,3}(?:_[A-Z][a-z]{3})?(?:_[A-Z]{2}|_[0-9]{3})?`
+
+// localeFilePattern matches file names of the form <prefix><locale><suffix>
+func localeFilePattern(prefix, suffix string) *regexp.Regexp {
+	return regexp.MustCompile("^" + regexp.QuoteMeta(prefix) + localePattern + regexp.QuoteMeta(suffix) + "$")
+}
+
+// handleOrphanedFiles warns about translation files of the directory that were not written by
+// the export, and removes them if requested. Only file names matching the pattern are
+// considered, so other files like package.json are never touched.
+func handleOrphanedFiles(baseDirectory string, pattern *regexp.Regexp, written map[string]bool, remove bool) error {
+	entries, err := os.ReadDir(baseDirectory)
+	if err != nil {
+		return fmt.Errorf("error listing %s: %v", baseDirectory, err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !pattern.MatchString(entry.Name()) {
+			continue
+		}
+		file := filepath.Join(baseDirectory, entry.Name())
+		if written[file] {
+			continue
+		}
+		if !remove {
+			fmt.Printf("Warning: orphaned translation file %s has no translations in the CSV\n", file)
+			continue
+		}
+		if err := os.Remove(file); err != nil {
+			return fmt.Errorf("error removing orphaned file %s: %v", file, err)
+		}
+		fmt.Printf("Removed orphaned translation file %s\n", file)
+	}
+	return nil
+}
+
+// buildOrderedJSON creates the flat or nested object for the given keys in order
+func buildOrderedJSON(flat map[string]string, keys []string, nested bool) *orderedObject {
+	root := newOrderedObject()
+	for _, key := range keys {
+		if !nested {
+			root.Set(key, flat[key])
+			continue
+		}
+
+		parts := strings.Split(key, ".")
+		node := root
+		for _, part := range parts[:len(parts)-1] {
+			child, ok := node.Get(part)
+			if !ok {
+				child = newOrderedObject()
+				node.Set(part, child)
+			}
+			node = child.(*orderedObject)
+		}
+		node.Set(parts[len(parts)-1], flat[key])
+	}
+	return root
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestHandleOrphanedFilesRemovesOnlyLocaleFiles(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"en.json", "de.json", "fr_CA.json", "package.json", "tsconfig.json", "app_de.arb"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("{}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	written := map[string]bool{filepath.Join(dir, "en.json"): true, filepath.Join(dir, "de.json"): true}
+	if err := handleOrphanedFiles(dir, localeFilePattern("", ".json"), written, true); err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]bool{"en.json": true, "de.json": true, "fr_CA.json": false, "package.json": true, "tsconfig.json": true, "app_de.arb": true} {
+		_, err := os.Stat(filepath.Join(dir, name))
+		if exists := err == nil; exists != want {
+			t.Errorf("%s exists = %v, want %v", name, exists, want)
+		}
+	}
+
+	if err := handleOrphanedFiles(dir, localeFilePattern("app_", ".arb"), nil, true); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "app_de.arb")); !os.IsNotExist(err) {
+		t.Errorf("orphaned app_de.arb was not removed")
+	}
+}
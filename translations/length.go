@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Units of a maximum length
+const (
+	LengthUnitCharacters = "chars"
+	LengthUnitPixels     = "px"
+)
+
+// placeholderSample is what a placeholder is assumed to expand to when measuring a text
+const placeholderSample = "0000"
+
+// LengthLimit is the maximum length of a row, in characters or estimated pixel width
+type LengthLimit struct {
+	Value int
+	Unit  string
+}
+
+// ParseLengthLimit parses a max_length value: "20" or "20chars" for characters, "120px" for
+// the estimated pixel width
+func ParseLengthLimit(raw string) (LengthLimit, error) {
+	raw = strings.TrimSpace(raw)
+	unit := LengthUnitCharacters
+	if strings.HasSuffix(raw, LengthUnitPixels) {
+		unit = LengthUnitPixels
+	}
+	number := strings.TrimSpace(strings.TrimSuffix(strings.TrimSuffix(raw, LengthUnitPixels), LengthUnitCharacters))
+
+	value, err := strconv.Atoi(number)
+	if err != nil || value <= 0 {
+		return LengthLimit{}, fmt.Errorf("invalid max length %q, expected e.g. 20 or 120px", raw)
+	}
+	return LengthLimit{Value: value, Unit: unit}, nil
+}
+
+func (l LengthLimit) String() string {
+	if l.Unit == LengthUnitPixels {
+		return fmt.Sprintf("%d%s", l.Value, LengthUnitPixels)
+	}
+	return strconv.Itoa(l.Value)
+}
+
+// Measure returns the length of a text in the unit of the limit
+func (l LengthLimit) Measure(text string) int {
+	text = displayText(text)
+	if l.Unit == LengthUnitPixels {
+		return estimatePixelWidth(text)
+	}
+	return utf8.RuneCountInString(text)
+}
+
+// Exceeds reports whether a text is longer than the limit
+func (l LengthLimit) Exceeds(text string) bool {
+	return l.Measure(text) > l.Value
+}
+
+// rowLengthLimit returns the parsed limit of a row, ok is false if it has none. Invalid
+// values are reported and ignored.
+func rowLengthLimit(row TranslationRow) (LengthLimit, bool) {
+	if row.MaxLength == "" {
+		return LengthLimit{}, false
+	}
+	limit, err := ParseLengthLimit(row.MaxLength)
+	if err != nil {
+		fmt.Printf("Warning: %s:%s: %v\n", row.App, row.Key, err)
+		return LengthLimit{}, false
+	}
+	return limit, true
+}
+
+// displayText approximates the text a user sees: placeholders are replaced by a sample, markup
+// is removed and of ICU messages the longest variant is taken
+func displayText(text string) string {
+	if nodes, err := ParseICU(text); err == nil && hasICUStructure(nodes) {
+		text = longestICUText(nodes)
+	}
+	text = htmlTagPattern.ReplaceAllString(text, "")
+	return placeholderPattern.ReplaceAllString(text, placeholderSample)
+}
+
+// longestICUText renders a message choosing the longest branch of every plural and select
+func longestICUText(nodes []ICUNode) string {
+	var sb strings.Builder
+	for _, node := range nodes {
+		switch {
+		case node.Kind == ICUText:
+			sb.WriteString(node.Text)
+		case node.Kind == ICUPound:
+			sb.WriteString(placeholderSample)
+		case len(node.Options) == 0:
+			sb.WriteString(placeholderSample)
+		default:
+			longest := ""
+			for _, option := range node.Options {
+				if text := longestICUText(option.Message); utf8.RuneCountInString(text) > utf8.RuneCountInString(longest) {
+					longest = text
+				}
+			}
+			sb.WriteString(longest)
+		}
+	}
+	return sb.String()
+}
+
+// estimatePixelWidth estimates the width of a text in a 14px proportional UI font
+func estimatePixelWidth(text string) int {
+	width := 0.0
+	for _, r := range text {
+		switch {
+		case strings.ContainsRune("iljtfrI.,;:!|'` ", r):
+			width += 4
+		case strings.ContainsRune("mwMW", r):
+			width += 12
+		case unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hangul, r) || unicode.Is(unicode.Hiragana, r) || unicode.Is(unicode.Katakana, r):
+			width += 14
+		case unicode.IsUpper(r):
+			width += 9
+		case unicode.IsDigit(r):
+			width += 8
+		default:
+			width += 7
+		}
+	}
+	return int(width + 0.5)
+}
+
+// SetMaxLength sets or, given an empty value, clears the limit of a key. Plural pairs are
+// limited as a whole.
+func (tm *Translations) SetMaxLength(app, key, maxLength string) error {
+	if maxLength != "" {
+		limit, err := ParseLengthLimit(maxLength)
+		if err != nil {
+			return err
+		}
+		maxLength = limit.String()
+	}
+
+	updated := 0
+	for _, suffix := range []string{"", ".singular", ".plural"} {
+		if i := tm.rowIndex(app, key+suffix); i >= 0 {
+			tm.Translations[i].MaxLength = maxLength
+			updated++
+		}
+	}
+	if updated == 0 {
+		return fmt.Errorf("key %s:%s not found", app, key)
+	}
+	return nil
+}
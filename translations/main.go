@@ -4,8 +4,11 @@ import (
 	"fmt"
 	"github.com/spf13/cobra"
 	"log"
+	"net/http"
+	"os"
 	"path"
 	"path/filepath"
+	"slices"
 )
 
 type Module struct {
@@ -13,80 +16,188 @@ type Module struct {
 	Path       string
 	ImportFunc func(translations *Translations) error
 	ExportFunc func(translations *Translations) error
+	// GenerateFunc writes typed accessors for the keys, nil if the platform has none
+	GenerateFunc func(translations *Translations) error
+	// GenerateOnExport regenerates the accessors on every export, otherwise they are only
+	// written by the generate command
+	GenerateOnExport bool
+	// Scanner finds key references in the app's source code, nil if it is not scanned
+	Scanner *SourceScanner
 }
 
-func getModules(tm *Translations, basePath string) []Module {
+func getModules(tm *Translations, basePath string, config ModuleConfig) []Module {
 	result := make([]Module, 0)
 
+	// Options come from the module configuration, the literals are the defaults
+	jsonOptions := func(app string, defaults JSONOptions) JSONOptions {
+		opts, err := config.JSONOptions(app, defaults)
+		if err != nil {
+			log.Fatalf("Failed to load module configuration: %v", err)
+		}
+		return opts
+	}
+	goAccessorOptions := func(app string) GoAccessorOptions {
+		opts := GoAccessorOptions{}
+		if err := config.Options(app, &opts); err != nil {
+			log.Fatalf("Failed to load module configuration: %v", err)
+		}
+		return opts
+	}
+	generateOnExport := func(app string, defaultValue bool) bool {
+		opts := moduleGenerateOptions{GenerateOnExport: defaultValue}
+		if err := config.Options(app, &opts); err != nil {
+			log.Fatalf("Failed to load module configuration: %v", err)
+		}
+		return opts.GenerateOnExport
+	}
+
 	// Android Module
+	androidStrings := filepath.Join(basePath, "android", "app", "src", "main", "java", "eu", "zeitkapsl", "i18n", "Strings.kt")
+	androidStringsOptions := KotlinOptions{Package: "eu.zeitkapsl.i18n", RClass: "eu.zeitkapsl.R", ObjectName: "Strings"}
 	result = append(result, Module{
 		App:  "android",
 		Path: basePath,
 		ImportFunc: func(tr *Translations) error {
-			return ImportFromAndroid(tm, basePath)
+			return ImportFromAndroid(tr, basePath)
 		},
 		ExportFunc: func(translations *Translations) error {
-			return ExportToAndroid(tm, basePath)
+			return ExportToAndroid(translations, basePath)
+		},
+		GenerateFunc: func(translations *Translations) error {
+			return GenerateKotlinStrings(translations, "android", androidStrings, androidStringsOptions)
 		},
+		Scanner: newAndroidScanner(basePath),
 	})
 
 	// iOS Module - ADDED THIS
+	iosStrings := filepath.Join(basePath, "ios", "Zeitkapsl", "Generated", "L10n.swift")
 	result = append(result, Module{
 		App:  "ios",
 		Path: filepath.Join(basePath, "ios", "Zeitkapsl", "Supporting Files"),
 		ImportFunc: func(tr *Translations) error {
-			return ImportFromXCStrings(tm, basePath)
+			return ImportFromXCStrings(tr, basePath)
 		},
 		ExportFunc: func(translations *Translations) error {
-			return ExportToXCStrings(tm, basePath)
+			return ExportToXCStrings(translations, basePath)
+		},
+		GenerateFunc: func(translations *Translations) error {
+			return GenerateSwiftStrings(translations, "ios", iosStrings, SwiftOptions{EnumName: "L10n"})
 		},
+		Scanner: newIOSScanner(basePath),
 	})
 
 	// Server Emails Module
 	serverMailsPath := path.Join(basePath, "server", "pkg", "mail", "templates")
+	serverMailsOptions := jsonOptions("server_emails", JSONOptions{Nested: false, PluralStyle: PluralStyleLegacy, KeyOrder: KeyOrderAlphabetical})
+	serverMailsAccessors := goAccessorOptions("server_emails")
 	result = append(result, Module{
 		App:  "server_emails",
 		Path: serverMailsPath,
 		ImportFunc: func(tr *Translations) error {
-			return ImportFromJSON(tm, "server_emails", serverMailsPath)
+			return ImportFromJSON(tr, "server_emails", serverMailsPath)
 		},
 		ExportFunc: func(translations *Translations) error {
-			return ExportToJson(tm, "server_emails", serverMailsPath)
+			return ExportToJson(translations, "server_emails", serverMailsPath, serverMailsOptions)
 		},
+		GenerateFunc: func(translations *Translations) error {
+			return GenerateGoAccessors(translations, "server_emails", serverMailsPath, serverMailsAccessors)
+		},
+		GenerateOnExport: generateOnExport("server_emails", false),
+		Scanner:          newGoScanner(path.Join(basePath, "server"), "templates", templateLookupFunctions, []string{".go", ".html", ".txt"}, templateKeyPattern),
 	})
 
 	// Core Module
 	coreTranslations := path.Join(basePath, "core", "pkg", "i18n")
+	coreOptions := jsonOptions("core", JSONOptions{Nested: false, PluralStyle: PluralStyleLegacy, KeyOrder: KeyOrderAlphabetical})
+	coreAccessors := goAccessorOptions("core")
 	result = append(result, Module{
 		App:  "core",
 		Path: coreTranslations,
 		ImportFunc: func(tr *Translations) error {
-			return ImportFromJSON(tm, "core", coreTranslations)
+			return ImportFromJSON(tr, "core", coreTranslations)
 		},
 		ExportFunc: func(translations *Translations) error {
-			return ExportToJson(tm, "core", coreTranslations)
+			return ExportToJson(translations, "core", coreTranslations, coreOptions)
+		},
+		GenerateFunc: func(translations *Translations) error {
+			return GenerateGoAccessors(translations, "core", coreTranslations, coreAccessors)
 		},
+		GenerateOnExport: generateOnExport("core", false),
+		Scanner:          newGoScanner(path.Join(basePath, "core"), "i18n", coreLookupFunctions, []string{".go"}),
 	})
 
 	// Web Module
 	webTranslations := path.Join(basePath, "web", "static", "translations")
+	webOptions := jsonOptions("web", JSONOptions{Nested: false, PluralStyle: PluralStyleLegacy, KeyOrder: KeyOrderAlphabetical})
+	webTypes := path.Join(basePath, "web", "src", "lib", "i18n", "translations.gen.ts")
 	result = append(result, Module{
 		App:  "web",
 		Path: webTranslations,
 		ImportFunc: func(tr *Translations) error {
-			return ImportFromJSON(tm, "web", webTranslations)
+			return ImportFromJSON(tr, "web", webTranslations)
 		},
 		ExportFunc: func(translations *Translations) error {
-			return ExportToJson(tm, "web", webTranslations)
+			return ExportToJson(translations, "web", webTranslations, webOptions)
+		},
+		GenerateFunc: func(translations *Translations) error {
+			return GenerateTypeScriptKeys(translations, "web", webTypes, TypeScriptOptions{PluralStyle: webOptions.PluralStyle})
 		},
+		GenerateOnExport: generateOnExport("web", true),
+		Scanner:          newWebScanner(basePath),
+	})
+
+	// Desktop Module (Flutter)
+	desktopTranslations := path.Join(basePath, "desktop", "lib", "l10n")
+	desktopOptions := jsonOptions("desktop", JSONOptions{KeyOrder: KeyOrderSource})
+	result = append(result, Module{
+		App:  "desktop",
+		Path: desktopTranslations,
+		ImportFunc: func(tr *Translations) error {
+			return ImportFromARB(tr, "desktop", desktopTranslations)
+		},
+		ExportFunc: func(translations *Translations) error {
+			return ExportToARB(translations, "desktop", desktopTranslations, "app", desktopOptions)
+		},
+		Scanner: newFlutterScanner(basePath),
 	})
 
 	return result
 }
 
+// addServiceFlags adds the flags to select a translation service and record or replay its traffic
+func addServiceFlags(cmd *cobra.Command) {
+	cmd.Flags().String("service", "auto", "Translation service to use (auto|deepl|azure|fake)")
+	cmd.Flags().String("record", "", "Record all service responses as fixtures into this directory")
+	cmd.Flags().String("replay", "", "Replay service responses from fixtures in this directory instead of calling the API")
+}
+
+// serviceFromFlags creates the translation service selected by the flags of addServiceFlags
+func serviceFromFlags(cmd *cobra.Command) (TranslationService, error) {
+	serviceType, _ := cmd.Flags().GetString("service")
+	recordDir, _ := cmd.Flags().GetString("record")
+	replayDir, _ := cmd.Flags().GetString("replay")
+
+	var client *http.Client
+	var err error
+	switch {
+	case recordDir != "" && replayDir != "":
+		return nil, fmt.Errorf("--record and --replay cannot be used together")
+	case recordDir != "":
+		client, err = NewRecordingClient(RecordMode, recordDir)
+	case replayDir != "":
+		client, err = NewRecordingClient(ReplayMode, replayDir)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return NewTranslationService(serviceType, client)
+}
+
 func main() {
 	var basePath string
 	var csvFile string
+	var memoryFile string
 
 	rootCmd := &cobra.Command{
 		Use:   "zeitkapsl-translations",
@@ -96,9 +207,14 @@ func main() {
 
 	rootCmd.PersistentFlags().StringVar(&basePath, "base-path", "../", "Base path to the translation files")
 	rootCmd.PersistentFlags().StringVar(&csvFile, "csv", "translations.csv", "CSV file path")
+	rootCmd.PersistentFlags().StringVar(&memoryFile, "memory", DefaultMemoryFile, "Translation memory file path")
 
 	tm := NewTranslations(basePath)
-	modules := getModules(tm, basePath)
+	moduleConfig, err := LoadModuleConfig(DefaultModuleConfigFile)
+	if err != nil {
+		log.Fatalf("Failed to load module configuration: %v", err)
+	}
+	modules := getModules(tm, basePath, moduleConfig)
 
 	// Import command
 	importCmd := &cobra.Command{
@@ -180,60 +296,776 @@ func main() {
 			}
 
 			platform, _ := cmd.Flags().GetString("platform")
-			
+			keepOrphans, _ := cmd.Flags().GetBool("keep-orphans")
+			pseudo, _ := cmd.Flags().GetBool("pseudo")
+
+			// Inherited values are resolved first, then only keys of the English source are
+			// exported unless orphans are kept
+			exported := tm.ResolveLinks()
+			if !keepOrphans {
+				exported = exported.WithoutOrphans()
+				if removed := len(tm.Translations) - len(exported.Translations); removed > 0 {
+					fmt.Printf("Skipping %d orphaned rows without English source, see the orphans command\n", removed)
+				}
+			}
+			// The pseudo locales are a preview, the generated accessors never list them
+			generated := exported
+			if pseudo {
+				exported = exported.WithPseudoLocales()
+			}
+
 			for _, m := range modules {
 				if platform != "all" && m.App != platform {
 					continue
 				}
 				
 				fmt.Printf("Exporting %s to %s\n", m.App, m.Path)
-				err := m.ExportFunc(tm)
+				err := m.ExportFunc(exported)
 				if err != nil {
 					fmt.Printf("Warning: Failed to export %s: %s\n", m.App, err.Error())
 					continue
 				}
+
+				// Generated accessors must follow the exported keys
+				if m.GenerateFunc != nil && m.GenerateOnExport {
+					if err := m.GenerateFunc(generated); err != nil {
+						fmt.Printf("Warning: Failed to generate accessors for %s: %s\n", m.App, err.Error())
+					}
+				}
 			}
 			fmt.Println("Export completed successfully!")
 		},
 	}
-	exportCmd.Flags().String("platform", "all", "Platform to export to (ios|android|web|core|server_emails|all)")
+	exportCmd.Flags().String("platform", "all", "Platform to export to (ios|android|web|core|server_emails|desktop|all)")
+	exportCmd.Flags().Bool("keep-orphans", false, "Also export keys that have no English source")
+	exportCmd.Flags().Bool("pseudo", false, "Also export the pseudo locales "+PseudoLocaleAccented+" and "+PseudoLocaleBidi+" generated from English")
+
+	// Generate command
+	generateCmd := &cobra.Command{
+		Use:   "generate",
+		Short: "Generate typed accessors for the translation keys",
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := LoadFromCSV(tm, csvFile); err != nil {
+				log.Fatalf("Failed to load CSV: %v", err)
+			}
+
+			platform, _ := cmd.Flags().GetString("platform")
+			for _, m := range modules {
+				if m.GenerateFunc == nil || (platform != "all" && m.App != platform) {
+					continue
+				}
+
+				fmt.Printf("Generating accessors for %s in %s\n", m.App, m.Path)
+				if err := m.GenerateFunc(tm.ResolveLinks()); err != nil {
+					fmt.Printf("Warning: Failed to generate accessors for %s: %s\n", m.App, err.Error())
+				}
+			}
+		},
+	}
+	generateCmd.Flags().String("platform", "all", "Platform to generate accessors for (ios|android|web|core|server_emails|all)")
+
+	// Orphans command
+	orphansCmd := &cobra.Command{
+		Use:   "orphans",
+		Short: "List translations whose key has no English source",
+		Run: func(cmd *cobra.Command, args []string) {
+			prune, _ := cmd.Flags().GetBool("prune")
+
+			if err := LoadFromCSV(tm, csvFile); err != nil {
+				log.Fatalf("Failed to load CSV: %v", err)
+			}
+
+			orphans := FindOrphans(tm)
+			for _, lang := range tm.Languages {
+				rows := orphans[lang]
+				if len(rows) == 0 {
+					continue
+				}
+				fmt.Printf("%s: %d orphaned translations\n", lang, len(rows))
+				for _, row := range rows {
+					fmt.Printf("  %s:%s = %s\n", row.App, row.Key, row.Values[lang])
+				}
+			}
+
+			if !prune {
+				return
+			}
+			// Only the reported rows are pruned, rows without any value are kept
+			before := len(tm.Translations)
+			for _, rows := range orphans {
+				tm.RemoveRows(rows)
+			}
+			removed := before - len(tm.Translations)
+			if err := SaveToCSV(tm, csvFile); err != nil {
+				log.Fatalf("Failed to save CSV: %v", err)
+			}
+			fmt.Printf("Pruned %d orphaned rows\n", removed)
+		},
+	}
+	orphansCmd.Flags().Bool("prune", false, "Remove orphaned rows from the CSV")
+
+	// Link command
+	linkCmd := &cobra.Command{
+		Use:   "link",
+		Short: "Let a key inherit the translations of a shared key unless overridden",
+		Run: func(cmd *cobra.Command, args []string) {
+			app, _ := cmd.Flags().GetString("app")
+			key, _ := cmd.Flags().GetString("key")
+			to, _ := cmd.Flags().GetString("to")
+			unlink, _ := cmd.Flags().GetBool("unlink")
+			if app == "" || key == "" || (to == "" && !unlink) {
+				log.Fatalf("Please specify --app, --key and --to or --unlink")
+			}
+
+			if err := LoadFromCSV(tm, csvFile); err != nil {
+				log.Fatalf("Failed to load CSV: %v", err)
+			}
+
+			if unlink {
+				if err := tm.UnlinkKey(app, key); err != nil {
+					log.Fatalf("Failed to unlink key: %v", err)
+				}
+				fmt.Printf("%s:%s no longer inherits, the inherited values were copied\n", app, key)
+			} else {
+				overrides, err := tm.LinkKey(app, key, to)
+				if err != nil {
+					log.Fatalf("Failed to link key: %v", err)
+				}
+				linkApp, linkKey := parseLink(to)
+				fmt.Printf("%s:%s now inherits %s:%s\n", app, key, linkApp, linkKey)
+				for _, override := range overrides {
+					fmt.Printf("  override %s\n", override)
+				}
+			}
+
+			if err := SaveToCSV(tm, csvFile); err != nil {
+				log.Fatalf("Failed to save CSV: %v", err)
+			}
+		},
+	}
+	linkCmd.Flags().String("app", "", "App of the inheriting key")
+	linkCmd.Flags().String("key", "", "Inheriting key, the base key for plurals")
+	linkCmd.Flags().String("to", "", "Inherited key as app:key, a key without app refers to the shared app")
+	linkCmd.Flags().Bool("unlink", false, "Stop inheriting and copy the inherited values into the key")
+
+	// Dedupe command
+	dedupeCmd := &cobra.Command{
+		Use:   "dedupe",
+		Short: "Report rows with the same English text and where their translations disagree",
+		Run: func(cmd *cobra.Command, args []string) {
+			apps, _ := cmd.Flags().GetStringSlice("app")
+			languages, _ := cmd.Flags().GetStringSlice("lang")
+			disagreementsOnly, _ := cmd.Flags().GetBool("disagreements-only")
+
+			if err := LoadFromCSV(tm, csvFile); err != nil {
+				log.Fatalf("Failed to load CSV: %v", err)
+			}
+
+			groups := FindDuplicates(tm, DedupeOptions{Apps: apps, Languages: languages, DisagreementsOnly: disagreementsOnly})
+			PrintDuplicateGroups(groups)
+		},
+	}
+	dedupeCmd.Flags().StringSlice("app", nil, "Only compare rows of these apps (e.g., android,web)")
+	dedupeCmd.Flags().StringSlice("lang", nil, "Only compare these languages (e.g., de)")
+	dedupeCmd.Flags().Bool("disagreements-only", false, "Only report groups whose translations disagree")
+
+	// Unused command
+	unusedCmd := &cobra.Command{
+		Use:   "unused",
+		Short: "List translation keys that are not referenced in the source code",
+		Run: func(cmd *cobra.Command, args []string) {
+			apps, _ := cmd.Flags().GetStringSlice("app")
+			ignore, _ := cmd.Flags().GetStringSlice("ignore")
+			remove, _ := cmd.Flags().GetBool("remove")
+
+			if err := LoadFromCSV(tm, csvFile); err != nil {
+				log.Fatalf("Failed to load CSV: %v", err)
+			}
+
+			unused := make([]TranslationRow, 0)
+			for _, m := range modules {
+				if m.Scanner == nil || (len(apps) > 0 && !slices.Contains(apps, m.App)) {
+					continue
+				}
+
+				references, err := m.Scanner.Scan()
+				if err != nil {
+					fmt.Printf("Warning: Failed to scan %s: %s\n", m.App, err.Error())
+					continue
+				}
+				// Without a single reference the sources are most likely missing
+				if len(references) == 0 {
+					fmt.Printf("Warning: No key references found for %s, skipping\n", m.App)
+					continue
+				}
+
+				rows := FindUnusedKeys(tm, m.App, resolveReferences(tm, m.App, m.Scanner, references), ignore)
+				for _, row := range rows {
+					fmt.Printf("  %s:%s = %s\n", row.App, row.Key, row.Values["en"])
+				}
+				fmt.Printf("%s: %d unused keys\n", m.App, len(rows))
+				unused = append(unused, rows...)
+			}
+
+			if !remove || len(unused) == 0 {
+				return
+			}
+			tm.RemoveRows(unused)
+			if err := SaveToCSV(tm, csvFile); err != nil {
+				log.Fatalf("Failed to save CSV: %v", err)
+			}
+			fmt.Printf("Removed %d unused rows\n", len(unused))
+		},
+	}
+	unusedCmd.Flags().StringSlice("app", nil, "Only scan these apps (e.g., web,android)")
+	unusedCmd.Flags().StringSlice("ignore", nil, "Key patterns that are never reported, e.g. keys built at runtime (e.g., Month*)")
+	unusedCmd.Flags().Bool("remove", false, "Remove unused rows from the CSV")
+
+	// Missing keys command
+	missingKeysCmd := &cobra.Command{
+		Use:   "missing-keys",
+		Short: "List keys referenced in the source code that have no translation row",
+		Run: func(cmd *cobra.Command, args []string) {
+			apps, _ := cmd.Flags().GetStringSlice("app")
+			create, _ := cmd.Flags().GetBool("create")
+
+			if err := LoadFromCSV(tm, csvFile); err != nil {
+				log.Fatalf("Failed to load CSV: %v", err)
+			}
+
+			missing := make([]MissingKey, 0)
+			for _, m := range modules {
+				if m.Scanner == nil || (len(apps) > 0 && !slices.Contains(apps, m.App)) {
+					continue
+				}
+
+				references, err := m.Scanner.Scan()
+				if err != nil {
+					fmt.Printf("Warning: Failed to scan %s: %s\n", m.App, err.Error())
+					continue
+				}
+
+				keys := FindMissingKeys(tm, m.App, resolveReferences(tm, m.App, m.Scanner, references))
+				for _, key := range keys {
+					fmt.Printf("  %s:%s used at %s", key.App, key.Key, key.Location())
+					if len(key.References) > 1 {
+						fmt.Printf(" and %d more", len(key.References)-1)
+					}
+					fmt.Println()
+				}
+				fmt.Printf("%s: %d missing keys\n", m.App, len(keys))
+				missing = append(missing, keys...)
+			}
+
+			if len(missing) == 0 {
+				return
+			}
+			if create {
+				AddMissingKeys(tm, missing)
+				if err := SaveToCSV(tm, csvFile); err != nil {
+					log.Fatalf("Failed to save CSV: %v", err)
+				}
+				fmt.Printf("Added %d empty rows to %s\n", len(missing), csvFile)
+			}
+			os.Exit(1)
+		},
+	}
+	missingKeysCmd.Flags().StringSlice("app", nil, "Only scan these apps (e.g., web,android)")
+	missingKeysCmd.Flags().Bool("create", false, "Add empty rows for the missing keys with their location as comment")
+
+	// moveKey moves or renames a key in the CSV and the translation memory, and optionally
+	// rewrites the references in the scanned source code and regenerates the accessors
+	moveKey := func(fromApp, oldKey, toApp, newKey string, rewriteCode bool) {
+		if err := LoadFromCSV(tm, csvFile); err != nil {
+			log.Fatalf("Failed to load CSV: %v", err)
+		}
+		memory, err := LoadTranslationMemory(memoryFile)
+		if err != nil {
+			log.Fatalf("Failed to load translation memory: %v", err)
+		}
+
+		// References of another platform's code cannot be rewritten mechanically, they are
+		// resolved before the move so accessors of the old key are still known
+		var references map[string][]KeyReference
+		var scanner *SourceScanner
+		for _, m := range modules {
+			if m.App == fromApp {
+				scanner = m.Scanner
+			}
+		}
+		if rewriteCode && scanner != nil && fromApp != toApp {
+			found, err := scanner.Scan()
+			if err != nil {
+				log.Fatalf("Failed to scan %s: %v", fromApp, err)
+			}
+			references = resolveReferences(tm, fromApp, scanner, found)
+		}
+
+		moved, err := tm.MoveKey(fromApp, oldKey, toApp, newKey)
+		if err != nil {
+			log.Fatalf("Failed to move key: %v", err)
+		}
+		for oldRowKey, newRowKey := range moved {
+			fmt.Printf("Moved %s:%s to %s:%s\n", fromApp, oldRowKey, toApp, newRowKey)
+		}
+		memory.MoveKey(fromApp, toApp, moved)
+
+		if err := SaveToCSV(tm, csvFile); err != nil {
+			log.Fatalf("Failed to save CSV: %v", err)
+		}
+		if err := memory.Save(); err != nil {
+			log.Fatalf("Failed to save translation memory: %v", err)
+		}
+
+		if !rewriteCode || scanner == nil || fromApp != toApp {
+			for _, m := range modules {
+				if m.GenerateFunc != nil && (m.App == fromApp || m.App == toApp) {
+					fmt.Printf("Run generate --platform %s to update the generated accessors\n", m.App)
+				}
+			}
+		}
+		if !rewriteCode || scanner == nil {
+			return
+		}
+		if fromApp != toApp {
+			for key, refs := range references {
+				if _, ok := moved[key]; !ok && moved[key+".singular"] == "" {
+					continue
+				}
+				for _, ref := range refs {
+					fmt.Printf("Warning: %s:%d still references %s:%s, update it manually\n", ref.File, ref.Line, fromApp, key)
+				}
+			}
+			return
+		}
+		count, err := scanner.RewriteKeys(moved)
+		if err != nil {
+			log.Fatalf("Failed to rewrite references: %v", err)
+		}
+		fmt.Printf("Rewrote %d references in the %s sources\n", count, fromApp)
+
+		// The rewritten references use the accessors of the new key
+		for _, m := range modules {
+			if m.App == fromApp && m.GenerateFunc != nil {
+				fmt.Printf("Generating accessors for %s in %s\n", m.App, m.Path)
+				if err := m.GenerateFunc(tm.ResolveLinks()); err != nil {
+					fmt.Printf("Warning: Failed to generate accessors for %s: %s\n", m.App, err.Error())
+				}
+			}
+		}
+	}
+
+	// Rename key command
+	renameKeyCmd := &cobra.Command{
+		Use:   "rename-key",
+		Short: "Rename a key in all languages, plural pairs are renamed as a whole",
+		Run: func(cmd *cobra.Command, args []string) {
+			app, _ := cmd.Flags().GetString("app")
+			oldKey, _ := cmd.Flags().GetString("old")
+			newKey, _ := cmd.Flags().GetString("new")
+			rewriteCode, _ := cmd.Flags().GetBool("rewrite-code")
+			if app == "" || oldKey == "" || newKey == "" {
+				log.Fatalf("Please specify --app, --old and --new")
+			}
+			moveKey(app, oldKey, app, newKey, rewriteCode)
+		},
+	}
+	renameKeyCmd.Flags().String("app", "", "App of the key")
+	renameKeyCmd.Flags().String("old", "", "Current key")
+	renameKeyCmd.Flags().String("new", "", "New key")
+	renameKeyCmd.Flags().Bool("rewrite-code", false, "Also rewrite the references in the app's source code")
+
+	// Move key command
+	moveKeyCmd := &cobra.Command{
+		Use:   "move-key",
+		Short: "Move a key to another app, e.g. android to core for shared strings",
+		Run: func(cmd *cobra.Command, args []string) {
+			fromApp, _ := cmd.Flags().GetString("from")
+			toApp, _ := cmd.Flags().GetString("to")
+			key, _ := cmd.Flags().GetString("key")
+			newKey, _ := cmd.Flags().GetString("new")
+			rewriteCode, _ := cmd.Flags().GetBool("rewrite-code")
+			if fromApp == "" || toApp == "" || key == "" {
+				log.Fatalf("Please specify --from, --to and --key")
+			}
+			moveKey(fromApp, key, toApp, newKey, rewriteCode)
+		},
+	}
+	moveKeyCmd.Flags().String("from", "", "App the key is moved from")
+	moveKeyCmd.Flags().String("to", "", "App the key is moved to")
+	moveKeyCmd.Flags().String("key", "", "Key to move")
+	moveKeyCmd.Flags().String("new", "", "New key in the target app (defaults to the current key)")
+	moveKeyCmd.Flags().Bool("rewrite-code", false, "Rewrite references within the same app, list the ones that need manual changes otherwise")
 
 	// Auto-translate command
 	autoTranslateCmd := &cobra.Command{
 		Use:   "auto-translate",
 		Short: "Auto-translate missing strings using AI (English as source)",
 		Run: func(cmd *cobra.Command, args []string) {
-			serviceType, _ := cmd.Flags().GetString("service")
+			state, _ := cmd.Flags().GetString("state")
+			opts := AutoTranslateOptions{}
+			opts.Apps, _ = cmd.Flags().GetStringSlice("app")
+			opts.KeyPatterns, _ = cmd.Flags().GetStringSlice("key")
+			opts.Languages, _ = cmd.Flags().GetStringSlice("lang")
+			opts.DryRun, _ = cmd.Flags().GetBool("dry-run")
 
-			var service TranslationService
-			switch serviceType {
-			case "auto", "":
-				service = GetTranslationService()
-				if service == nil {
-					fmt.Println("No translation service configured. Please set DEEPL_API_KEY or AZURE_TRANSLATOR_KEY/AZURE_TRANSLATOR_REGION environment variables.")
-					return
-				}
+			switch state {
+			case "missing":
+			case "stale":
+				opts.IncludeStale = true
 			default:
-				log.Fatalf("Unknown service: %s. Use 'deepl', 'azure', or 'auto'", serviceType)
+				log.Fatalf("Unknown state: %s. Use 'missing' or 'stale'", state)
+			}
+
+			service, err := serviceFromFlags(cmd)
+			if err != nil {
+				log.Fatal(err)
+			}
+			if service == nil && !opts.DryRun {
+				fmt.Println("No translation service configured. Please set DEEPL_API_KEY or AZURE_TRANSLATOR_KEY/AZURE_TRANSLATOR_REGION environment variables.")
+				return
 			}
 
 			if err := LoadFromCSV(tm, csvFile); err != nil {
 				log.Fatalf("Failed to load CSV: %v", err)
 			}
 
-			count, err := AutoTranslateFromEnglish(tm, service)
+			memory, err := LoadTranslationMemory(memoryFile)
+			if err != nil {
+				log.Fatalf("Failed to load translation memory: %v", err)
+			}
+			fmt.Printf("Harvested %d existing translations into memory\n", memory.Harvest(tm, "en"))
+
+			count, err := AutoTranslateFromEnglish(tm, service, memory, opts)
 			if err != nil {
 				log.Fatalf("Auto-translate failed: %v", err)
 			}
+			if opts.DryRun {
+				return
+			}
 
 			if err := SaveToCSV(tm, csvFile); err != nil {
 				log.Fatalf("Failed to save CSV: %v", err)
 			}
+			if err := memory.Save(); err != nil {
+				log.Fatalf("Failed to save translation memory: %v", err)
+			}
 
 			fmt.Printf("Auto-translation completed. Translated %d strings from English.\n", count)
 		},
 	}
-	autoTranslateCmd.Flags().String("service", "auto", "Translation service to use (auto|deepl|azure)")
+	addServiceFlags(autoTranslateCmd)
+	autoTranslateCmd.Flags().StringSlice("app", nil, "Only translate these apps (e.g., android,web)")
+	autoTranslateCmd.Flags().StringSlice("key", nil, "Only translate keys matching these globs (e.g., 'settings.*')")
+	autoTranslateCmd.Flags().StringSlice("lang", nil, "Only translate into these languages (e.g., de,fr)")
+	autoTranslateCmd.Flags().String("state", "missing", "Which cells to translate (missing|stale), stale includes missing")
+	autoTranslateCmd.Flags().Bool("dry-run", false, "Print strings, characters and estimated cost without translating")
+
+	// Adapt regions command
+	adaptRegionsCmd := &cobra.Command{
+		Use:   "adapt-regions",
+		Short: "Adapt regional variants from their base language (e.g., de → de_AT)",
+		Run: func(cmd *cobra.Command, args []string) {
+			dictionaryFile, _ := cmd.Flags().GetString("dictionary")
+			useLLM, _ := cmd.Flags().GetBool("llm")
+			opts := AutoTranslateOptions{}
+			opts.Apps, _ = cmd.Flags().GetStringSlice("app")
+			opts.KeyPatterns, _ = cmd.Flags().GetStringSlice("key")
+			opts.Languages, _ = cmd.Flags().GetStringSlice("region")
+
+			var adapter RegionAdapter
+			if useLLM {
+				adapter = GetRegionAdapter()
+				if adapter == nil {
+					log.Fatal("No LLM configured. Please set the OPENAI_API_KEY environment variable.")
+				}
+			}
+
+			dict, err := LoadRegionDictionary(dictionaryFile)
+			if err != nil {
+				log.Fatalf("Failed to load region dictionary: %v", err)
+			}
+
+			if err := LoadFromCSV(tm, csvFile); err != nil {
+				log.Fatalf("Failed to load CSV: %v", err)
+			}
+
+			count, err := AdaptRegions(tm, dict, adapter, opts)
+			if err != nil {
+				log.Fatalf("Region adaptation failed: %v", err)
+			}
+
+			if err := SaveToCSV(tm, csvFile); err != nil {
+				log.Fatalf("Failed to save CSV: %v", err)
+			}
+
+			fmt.Printf("Region adaptation completed. Added %d regional overrides.\n", count)
+		},
+	}
+	adaptRegionsCmd.Flags().StringSlice("region", nil, "Only adapt these regions (e.g., de_AT)")
+	adaptRegionsCmd.Flags().StringSlice("app", nil, "Only adapt these apps (e.g., android,web)")
+	adaptRegionsCmd.Flags().StringSlice("key", nil, "Only adapt keys matching these globs (e.g., 'Month*')")
+	adaptRegionsCmd.Flags().String("dictionary", DefaultRegionDictionaryFile, "Regional substitution dictionary file path")
+	adaptRegionsCmd.Flags().Bool("llm", false, "Let an LLM adapt the text after the dictionary (requires OPENAI_API_KEY)")
+
+	// Quality check command
+	qualityCheckCmd := &cobra.Command{
+		Use:   "quality-check",
+		Short: "Back-translate machine translations to English and report suspicious ones",
+		Run: func(cmd *cobra.Command, args []string) {
+			output, _ := cmd.Flags().GetString("output")
+			opts := QualityOptions{}
+			opts.Apps, _ = cmd.Flags().GetStringSlice("app")
+			opts.Languages, _ = cmd.Flags().GetStringSlice("lang")
+			opts.All, _ = cmd.Flags().GetBool("all")
+			opts.MinSimilarity, _ = cmd.Flags().GetFloat64("min-similarity")
+			opts.MinLengthRatio, _ = cmd.Flags().GetFloat64("min-length-ratio")
+			opts.MaxLengthRatio, _ = cmd.Flags().GetFloat64("max-length-ratio")
+
+			service, err := serviceFromFlags(cmd)
+			if err != nil {
+				log.Fatal(err)
+			}
+			if service == nil {
+				fmt.Println("No translation service configured. Please set DEEPL_API_KEY or AZURE_TRANSLATOR_KEY/AZURE_TRANSLATOR_REGION environment variables.")
+				return
+			}
+
+			if err := LoadFromCSV(tm, csvFile); err != nil {
+				log.Fatalf("Failed to load CSV: %v", err)
+			}
+			memory, err := LoadTranslationMemory(memoryFile)
+			if err != nil {
+				log.Fatalf("Failed to load translation memory: %v", err)
+			}
+
+			results, err := CheckTranslationQuality(tm, service, memory, opts)
+			if err != nil {
+				log.Fatalf("Quality check failed: %v", err)
+			}
+			if err := SaveQualityReport(results, output); err != nil {
+				log.Fatalf("Failed to save quality report: %v", err)
+			}
+		},
+	}
+	qualityCheckCmd.Flags().StringSlice("app", nil, "Only check these apps (e.g., android,web)")
+	qualityCheckCmd.Flags().StringSlice("lang", nil, "Only check these languages (e.g., de,fr)")
+	qualityCheckCmd.Flags().Bool("all", false, "Check all translations, not only machine translations")
+	qualityCheckCmd.Flags().Float64("min-similarity", 0.5, "Back-translations below this similarity (0-1) are suspicious")
+	qualityCheckCmd.Flags().Float64("min-length-ratio", 0.5, "Translations shorter than this ratio of the English text are suspicious")
+	qualityCheckCmd.Flags().Float64("max-length-ratio", 2.0, "Translations longer than this ratio of the English text are suspicious")
+	addServiceFlags(qualityCheckCmd)
+	qualityCheckCmd.Flags().String("output", DefaultQualityReportFile, "Report file path")
+
+	// Validate command
+	validateCmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Check ICU syntax, arguments, plural categories and placeholders against English",
+		Run: func(cmd *cobra.Command, args []string) {
+			opts := ValidationOptions{}
+			opts.Apps, _ = cmd.Flags().GetStringSlice("app")
+			opts.Languages, _ = cmd.Flags().GetStringSlice("lang")
+			opts.MaxLengthRatio, _ = cmd.Flags().GetFloat64("max-length-ratio")
+
+			if err := LoadFromCSV(tm, csvFile); err != nil {
+				log.Fatalf("Failed to load CSV: %v", err)
+			}
+
+			if errors := PrintValidationIssues(ValidateTranslations(tm, opts)); errors > 0 {
+				os.Exit(1)
+			}
+		},
+	}
+	validateCmd.Flags().StringSlice("app", nil, "Only validate these apps (e.g., android,web)")
+	validateCmd.Flags().StringSlice("lang", nil, "Only validate these languages (e.g., de,fr)")
+	validateCmd.Flags().Float64("max-length-ratio", 1.5, "Warn about translations this many times longer than English, 0 disables it")
+
+	// Terminology command
+	terminologyCmd := &cobra.Command{
+		Use:   "terminology",
+		Short: "Check translations against the glossary: required terms, untranslated names and forbidden terms",
+		Run: func(cmd *cobra.Command, args []string) {
+			glossaryFile, _ := cmd.Flags().GetString("glossary")
+			opts := TerminologyOptions{}
+			opts.Apps, _ = cmd.Flags().GetStringSlice("app")
+			opts.Languages, _ = cmd.Flags().GetStringSlice("lang")
+
+			if err := LoadFromCSV(tm, csvFile); err != nil {
+				log.Fatalf("Failed to load CSV: %v", err)
+			}
+
+			glossary, err := LoadGlossary(glossaryFile)
+			if err != nil {
+				log.Fatalf("Failed to load glossary: %v", err)
+			}
+			if len(glossary.Terms) == 0 && len(glossary.Forbidden) == 0 {
+				fmt.Printf("Warning: glossary %s has no terms\n", glossaryFile)
+			}
+
+			if errors := PrintValidationIssues(CheckTerminology(tm, glossary, opts)); errors > 0 {
+				os.Exit(1)
+			}
+		},
+	}
+	terminologyCmd.Flags().String("glossary", DefaultGlossaryFile, "Glossary file path")
+	terminologyCmd.Flags().StringSlice("app", nil, "Only check these apps (e.g., android,web)")
+	terminologyCmd.Flags().StringSlice("lang", nil, "Only check these languages (e.g., de,fr)")
+
+	// Typography command
+	typographyCmd := &cobra.Command{
+		Use:   "typography",
+		Short: "Check quotation marks, ellipses, French no-break spaces and whitespace per locale",
+		Run: func(cmd *cobra.Command, args []string) {
+			fix, _ := cmd.Flags().GetBool("fix")
+			opts := TypographyOptions{}
+			opts.Apps, _ = cmd.Flags().GetStringSlice("app")
+			opts.Languages, _ = cmd.Flags().GetStringSlice("lang")
+			opts.IncludeSource, _ = cmd.Flags().GetBool("include-source")
+
+			if err := LoadFromCSV(tm, csvFile); err != nil {
+				log.Fatalf("Failed to load CSV: %v", err)
+			}
+
+			// Like in validate only errors fail the check, wrong quotation marks are errors
+			issues := LintTypography(tm, opts, fix)
+			errors := PrintValidationIssues(issues)
+
+			if !fix {
+				if errors > 0 {
+					os.Exit(1)
+				}
+				return
+			}
+			if len(issues) > 0 {
+				if err := SaveToCSV(tm, csvFile); err != nil {
+					log.Fatalf("Failed to save CSV: %v", err)
+				}
+				fmt.Printf("Fixed %d typography issues\n", len(issues))
+			}
+		},
+	}
+	typographyCmd.Flags().Bool("fix", false, "Rewrite the CSV with the issues fixed")
+	typographyCmd.Flags().StringSlice("app", nil, "Only check these apps (e.g., android,web)")
+	typographyCmd.Flags().StringSlice("lang", nil, "Only check these languages (e.g., de,fr)")
+	typographyCmd.Flags().Bool("include-source", false, "Also check and fix the English source")
+
+	// Spellcheck command
+	spellcheckCmd := &cobra.Command{
+		Use:   "spellcheck",
+		Short: "Check the spelling of translations with local Hunspell dictionaries and the project word list",
+		Run: func(cmd *cobra.Command, args []string) {
+			directory, _ := cmd.Flags().GetString("dictionaries")
+			wordListFile, _ := cmd.Flags().GetString("words")
+			glossaryFile, _ := cmd.Flags().GetString("glossary")
+			opts := SpellcheckOptions{}
+			opts.Apps, _ = cmd.Flags().GetStringSlice("app")
+			opts.Languages, _ = cmd.Flags().GetStringSlice("lang")
+
+			if err := LoadFromCSV(tm, csvFile); err != nil {
+				log.Fatalf("Failed to load CSV: %v", err)
+			}
+
+			words, err := LoadWordList(wordListFile)
+			if err != nil {
+				log.Fatalf("Failed to load word list: %v", err)
+			}
+			glossary, err := LoadGlossary(glossaryFile)
+			if err != nil {
+				log.Fatalf("Failed to load glossary: %v", err)
+			}
+			words.AddGlossary(glossary)
+
+			languages := opts.Languages
+			if len(languages) == 0 {
+				languages = tm.Languages
+			}
+			dictionaries, err := LoadDictionaries(directory, languages)
+			if err != nil {
+				log.Fatalf("Failed to load dictionaries: %v", err)
+			}
+			if len(dictionaries) == 0 {
+				log.Fatalf("No dictionaries found in %s, expected Hunspell files like de_DE.dic and de_DE.aff", directory)
+			}
+
+			unknown := CheckSpelling(tm, dictionaries, words, opts)
+			PrintUnknownWords(unknown)
+			fmt.Printf("Found %d unknown words\n", len(unknown))
+			if len(unknown) > 0 {
+				os.Exit(1)
+			}
+		},
+	}
+	spellcheckCmd.Flags().String("dictionaries", DefaultDictionaryDirectory, "Directory with the Hunspell .dic and .aff files")
+	spellcheckCmd.Flags().String("words", DefaultWordListFile, "Project word list, one word per line")
+	spellcheckCmd.Flags().String("glossary", DefaultGlossaryFile, "Glossary file path, its terms are always accepted")
+	spellcheckCmd.Flags().StringSlice("app", nil, "Only check these apps (e.g., android,web)")
+	spellcheckCmd.Flags().StringSlice("lang", nil, "Only check these languages (e.g., de,en)")
+
+	// Set max length command
+	setMaxLengthCmd := &cobra.Command{
+		Use:   "set-max-length",
+		Short: "Limit the length of a key in characters (e.g., 20) or estimated pixels (e.g., 120px)",
+		Run: func(cmd *cobra.Command, args []string) {
+			app, _ := cmd.Flags().GetString("app")
+			key, _ := cmd.Flags().GetString("key")
+			maxLength, _ := cmd.Flags().GetString("max")
+			if app == "" || key == "" {
+				log.Fatalf("Please specify --app and --key")
+			}
+
+			if err := LoadFromCSV(tm, csvFile); err != nil {
+				log.Fatalf("Failed to load CSV: %v", err)
+			}
+
+			if err := tm.SetMaxLength(app, key, maxLength); err != nil {
+				log.Fatalf("Failed to set max length: %v", err)
+			}
+
+			if err := SaveToCSV(tm, csvFile); err != nil {
+				log.Fatalf("Failed to save CSV: %v", err)
+			}
+			if maxLength == "" {
+				fmt.Printf("Removed the length limit of %s:%s\n", app, key)
+			} else {
+				fmt.Printf("Limited %s:%s to %s\n", app, key, maxLength)
+			}
+		},
+	}
+	setMaxLengthCmd.Flags().String("app", "", "App of the key")
+	setMaxLengthCmd.Flags().String("key", "", "Key to limit, the base key for plurals")
+	setMaxLengthCmd.Flags().String("max", "", "Maximum length, e.g. 20 or 120px; empty removes the limit")
+
+	// Preview emails command
+	previewEmailsCmd := &cobra.Command{
+		Use:   "preview-emails",
+		Short: "Render the server email templates per language into static HTML and text files",
+		Run: func(cmd *cobra.Command, args []string) {
+			output, _ := cmd.Flags().GetString("output")
+			languages, _ := cmd.Flags().GetStringSlice("lang")
+
+			if err := LoadFromCSV(tm, csvFile); err != nil {
+				log.Fatalf("Failed to load CSV: %v", err)
+			}
+			if len(languages) == 0 {
+				languages = tm.Languages
+			}
+
+			templateDirectory := ""
+			for _, m := range modules {
+				if m.App == "server_emails" {
+					templateDirectory = m.Path
+				}
+			}
+
+			count, err := RenderEmailPreviews(tm, "server_emails", templateDirectory, output, languages)
+			if err != nil {
+				log.Fatalf("Failed to render email previews: %v", err)
+			}
+			fmt.Printf("Rendered %d email previews into %s\n", count, output)
+		},
+	}
+	previewEmailsCmd.Flags().String("output", DefaultPreviewDirectory, "Output directory for the previews")
+	previewEmailsCmd.Flags().StringSlice("lang", nil, "Only render these languages (e.g., de,en)")
 
 	// Status command - NEW
 	statusCmd := &cobra.Command{
@@ -264,7 +1096,98 @@ func main() {
 		},
 	}
 
-	rootCmd.AddCommand(importCmd, addLangCmd, addRegionCmd, exportCmd, autoTranslateCmd, statusCmd)
+	// Suggest command
+	suggestCmd := &cobra.Command{
+		Use:   "suggest",
+		Short: "Show fuzzy translation memory matches for missing strings",
+		Run: func(cmd *cobra.Command, args []string) {
+			lang, _ := cmd.Flags().GetString("lang")
+			if lang == "" {
+				log.Fatal("--lang flag is required")
+			}
+			minScore, _ := cmd.Flags().GetFloat64("min-score")
+			limit, _ := cmd.Flags().GetInt("limit")
+
+			if err := LoadFromCSV(tm, csvFile); err != nil {
+				log.Fatalf("Failed to load CSV: %v", err)
+			}
+			memory, err := LoadTranslationMemory(memoryFile)
+			if err != nil {
+				log.Fatalf("Failed to load translation memory: %v", err)
+			}
+			memory.Harvest(tm, "en")
+
+			count := 0
+			for _, row := range tm.Translations {
+				source := row.Values["en"]
+				if source == "" || row.Values[lang] != "" {
+					continue
+				}
+				matches := memory.Fuzzy(source, "en", lang, minScore, limit)
+				if len(matches) == 0 {
+					continue
+				}
+				count++
+				fmt.Printf("%s:%s\n  en: %s\n", row.App, row.Key, source)
+				for _, match := range matches {
+					fmt.Printf("  %3.0f%% %s -> %s (%s)\n", match.Score*100, match.Entry.Source, match.Entry.Target, match.Entry.Provider)
+				}
+			}
+			fmt.Printf("\nFound suggestions for %d missing %s strings\n", count, lang)
+		},
+	}
+	suggestCmd.Flags().String("lang", "", "Language to show suggestions for (e.g., de, fr)")
+	suggestCmd.Flags().Float64("min-score", 0.7, "Minimum similarity between 0 and 1")
+	suggestCmd.Flags().Int("limit", 3, "Maximum number of suggestions per string")
+
+	// Review command
+	reviewCmd := &cobra.Command{
+		Use:   "review",
+		Short: "Mark the current translations of a key as reviewed, so the memory prefers them",
+		Run: func(cmd *cobra.Command, args []string) {
+			app, _ := cmd.Flags().GetString("app")
+			key, _ := cmd.Flags().GetString("key")
+			languages, _ := cmd.Flags().GetStringSlice("lang")
+			if app == "" || key == "" {
+				log.Fatalf("Please specify --app and --key")
+			}
+
+			if err := LoadFromCSV(tm, csvFile); err != nil {
+				log.Fatalf("Failed to load CSV: %v", err)
+			}
+			memory, err := LoadTranslationMemory(memoryFile)
+			if err != nil {
+				log.Fatalf("Failed to load translation memory: %v", err)
+			}
+
+			row := tm.GetRow(app, key)
+			if row == nil {
+				log.Fatalf("Key %s:%s not found", app, key)
+			}
+			if len(languages) == 0 {
+				for _, lang := range tm.Languages {
+					if lang != "en" && row.Values[lang] != "" {
+						languages = append(languages, lang)
+					}
+				}
+			}
+			for _, lang := range languages {
+				if err := memory.MarkReviewed(*row, "en", lang); err != nil {
+					log.Fatalf("Failed to mark as reviewed: %v", err)
+				}
+				fmt.Printf("Reviewed %s:%s [%s]: %s\n", app, key, lang, row.Values[lang])
+			}
+
+			if err := memory.Save(); err != nil {
+				log.Fatalf("Failed to save translation memory: %v", err)
+			}
+		},
+	}
+	reviewCmd.Flags().String("app", "", "App of the key")
+	reviewCmd.Flags().String("key", "", "Key whose translations were reviewed")
+	reviewCmd.Flags().StringSlice("lang", nil, "Reviewed languages, all translated languages by default")
+
+	rootCmd.AddCommand(importCmd, addLangCmd, addRegionCmd, exportCmd, generateCmd, orphansCmd, linkCmd, dedupeCmd, unusedCmd, missingKeysCmd, renameKeyCmd, moveKeyCmd, autoTranslateCmd, adaptRegionsCmd, qualityCheckCmd, previewEmailsCmd, statusCmd, suggestCmd, reviewCmd, validateCmd, terminologyCmd, typographyCmd, spellcheckCmd, setMaxLengthCmd)
 
 	if err := rootCmd.Execute(); err != nil {
 		log.Fatal(err)
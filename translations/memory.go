@@ -0,0 +1,262 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultMemoryFile is the default filename for the translation memory
+const DefaultMemoryFile = "translation_memory.json"
+
+// Providers of memory entries that were not machine translated in this tool
+const (
+	ProviderCSV      = "csv"      // harvested from existing CSV values, provenance unknown
+	ProviderReviewed = "reviewed" // confirmed by a human with the review command
+)
+
+// MemoryEntry is a single source→target pair with its provenance
+type MemoryEntry struct {
+	SourceLang string    `json:"source_lang"`
+	TargetLang string    `json:"target_lang"`
+	Source     string    `json:"source"`
+	Target     string    `json:"target"`
+	Provider   string    `json:"provider"`
+	App        string    `json:"app,omitempty"`
+	Key        string    `json:"key,omitempty"`
+	Reused     bool      `json:"reused,omitempty"`
+	Created    time.Time `json:"created"`
+}
+
+// FuzzyMatch is a memory entry that is similar to a requested source text
+type FuzzyMatch struct {
+	Entry MemoryEntry
+	Score float64
+}
+
+// TranslationMemory stores every translation that was produced or reviewed
+// so identical strings are only sent to a provider once
+type TranslationMemory struct {
+	Entries  []MemoryEntry
+	filename string
+}
+
+// LoadTranslationMemory loads the memory from a JSON file, a missing file yields an empty memory
+func LoadTranslationMemory(filename string) (*TranslationMemory, error) {
+	if filename == "" {
+		filename = DefaultMemoryFile
+	}
+
+	memory := &TranslationMemory{
+		Entries:  make([]MemoryEntry, 0),
+		filename: filename,
+	}
+
+	data, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return memory, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading translation memory %s: %v", filename, err)
+	}
+
+	if err := json.Unmarshal(data, &memory.Entries); err != nil {
+		return nil, fmt.Errorf("error parsing translation memory %s: %v", filename, err)
+	}
+	return memory, nil
+}
+
+// Save writes the memory back to the file it was loaded from
+func (m *TranslationMemory) Save() error {
+	sort.SliceStable(m.Entries, func(i, j int) bool {
+		a, b := m.Entries[i], m.Entries[j]
+		if a.TargetLang != b.TargetLang {
+			return a.TargetLang < b.TargetLang
+		}
+		if a.App != b.App {
+			return a.App < b.App
+		}
+		return a.Key < b.Key
+	})
+
+	data, err := json.MarshalIndent(m.Entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding translation memory: %v", err)
+	}
+	if err := os.WriteFile(m.filename, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("error writing translation memory %s: %v", m.filename, err)
+	}
+	fmt.Printf("Saved %d memory entries to %s\n", len(m.Entries), m.filename)
+	return nil
+}
+
+// Record adds an entry, replacing an older entry for the same cell and source text
+func (m *TranslationMemory) Record(entry MemoryEntry) {
+	if entry.Created.IsZero() {
+		entry.Created = time.Now().UTC()
+	}
+	for i, e := range m.Entries {
+		if e.App == entry.App && e.Key == entry.Key && e.SourceLang == entry.SourceLang &&
+			e.TargetLang == entry.TargetLang && e.Source == entry.Source {
+			m.Entries[i] = entry
+			return
+		}
+	}
+	m.Entries = append(m.Entries, entry)
+}
+
+// Lookup returns an exact match for the source text. Reviewed entries come first, then machine
+// translations and last harvested CSV values of unknown quality, the newest entry on a tie.
+func (m *TranslationMemory) Lookup(source, sourceLang, targetLang string) *MemoryEntry {
+	var best *MemoryEntry
+	for i, e := range m.Entries {
+		if e.Source != source || e.SourceLang != sourceLang || e.TargetLang != targetLang || e.Target == "" {
+			continue
+		}
+		if best == nil || providerRank(e.Provider) > providerRank(best.Provider) ||
+			(providerRank(e.Provider) == providerRank(best.Provider) && e.Created.After(best.Created)) {
+			best = &m.Entries[i]
+		}
+	}
+	return best
+}
+
+// providerRank orders the providers by how much their translations can be trusted
+func providerRank(provider string) int {
+	switch provider {
+	case ProviderReviewed:
+		return 2
+	case ProviderCSV:
+		return 0
+	default:
+		return 1
+	}
+}
+
+// MarkReviewed records the current value of a cell as reviewed by a human
+func (m *TranslationMemory) MarkReviewed(row TranslationRow, sourceLang, lang string) error {
+	source, value := row.Values[sourceLang], row.Values[lang]
+	if source == "" || value == "" {
+		return fmt.Errorf("%s:%s has no %s source or no %s value", row.App, row.Key, sourceLang, lang)
+	}
+	m.Record(MemoryEntry{
+		SourceLang: sourceLang,
+		TargetLang: lang,
+		Source:     source,
+		Target:     value,
+		Provider:   ProviderReviewed,
+		App:        row.App,
+		Key:        row.Key,
+	})
+	return nil
+}
+
+// CellEntry returns the entry that produced the current value of a cell, if any
+func (m *TranslationMemory) CellEntry(app, key, targetLang, value string) *MemoryEntry {
+	var latest *MemoryEntry
+	for i, e := range m.Entries {
+		if e.App != app || e.Key != key || e.TargetLang != targetLang || e.Target != value {
+			continue
+		}
+		if latest == nil || e.Created.After(latest.Created) {
+			latest = &m.Entries[i]
+		}
+	}
+	return latest
+}
+
+// Fuzzy returns up to limit entries whose source text is similar to the given text
+func (m *TranslationMemory) Fuzzy(source, sourceLang, targetLang string, minScore float64, limit int) []FuzzyMatch {
+	result := make([]FuzzyMatch, 0)
+	seen := make(map[string]bool)
+	for _, e := range m.Entries {
+		if e.SourceLang != sourceLang || e.TargetLang != targetLang || e.Target == "" || e.Source == source {
+			continue
+		}
+		if seen[e.Source+"\x00"+e.Target] {
+			continue
+		}
+		score := similarity(source, e.Source)
+		if score < minScore {
+			continue
+		}
+		seen[e.Source+"\x00"+e.Target] = true
+		result = append(result, FuzzyMatch{Entry: e, Score: score})
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Score > result[j].Score
+	})
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
+
+// Harvest records all existing translations of the CSV whose provenance is unknown,
+// so they can be reused for identical strings in other apps
+func (m *TranslationMemory) Harvest(tm *Translations, sourceLang string) int {
+	count := 0
+	for _, row := range tm.Translations {
+		source := row.Values[sourceLang]
+		if source == "" {
+			continue
+		}
+		for lang, value := range row.Values {
+			if lang == sourceLang || value == "" {
+				continue
+			}
+			if m.CellEntry(row.App, row.Key, lang, value) != nil {
+				continue
+			}
+			m.Record(MemoryEntry{
+				SourceLang: sourceLang,
+				TargetLang: lang,
+				Source:     source,
+				Target:     value,
+				Provider:   ProviderCSV,
+				App:        row.App,
+				Key:        row.Key,
+			})
+			count++
+		}
+	}
+	return count
+}
+
+// similarity returns a score between 0 and 1 based on the Levenshtein distance
+func similarity(a, b string) float64 {
+	ra := []rune(strings.ToLower(a))
+	rb := []rune(strings.ToLower(b))
+	longest := len(ra)
+	if len(rb) > longest {
+		longest = len(rb)
+	}
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+)
+
+// MissingKey is a key referenced in the source code without a row in the translations
+type MissingKey struct {
+	App        string
+	Key        string
+	Plural     bool
+	References []KeyReference
+}
+
+// Location returns the first reference as file:line
+func (m MissingKey) Location() string {
+	if len(m.References) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s:%d", m.References[0].File, m.References[0].Line)
+}
+
+// FindMissingKeys returns the referenced keys of an app that have no row, sorted by key.
+// A key is present if it has a row itself or is the base key of a plural pair.
+func FindMissingKeys(tm *Translations, app string, references map[string][]KeyReference) []MissingKey {
+	existing := make(map[string]bool)
+	for _, row := range tm.GetTranslationsForApp(app) {
+		existing[row.Key] = true
+		if row.IsPlural() {
+			existing[row.GetSingularKey()] = true
+		}
+	}
+
+	result := make([]MissingKey, 0)
+	for key, refs := range references {
+		if existing[key] {
+			continue
+		}
+		missing := MissingKey{App: app, Key: key, References: refs}
+		for _, ref := range refs {
+			missing.Plural = missing.Plural || ref.Plural
+		}
+		result = append(result, missing)
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Key < result[j].Key })
+	return result
+}
+
+// AddMissingKeys creates empty rows for missing keys with their first reference as comment
+func AddMissingKeys(tm *Translations, missing []MissingKey) {
+	for _, m := range missing {
+		if m.Plural {
+			tm.SetPluralTranslation(m.App, m.Key, "en", "", "", m.Location())
+		} else {
+			tm.SetTranslation(m.App, m.Key, "en", "", m.Location())
+		}
+	}
+	tm.Sort()
+}
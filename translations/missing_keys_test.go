@@ -0,0 +1,51 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindMissingKeysGoLookups(t *testing.T) {
+	root := t.TempDir()
+	source := `package sync
+
+func status() string {
+	i18n.SetLanguage("de")
+	files, _ := i18n.Files.ReadFile("en.json")
+	_ = i18n.Load("en.json", files)
+	_ = i18n.T("sync.done")
+	_ = i18n.KeySyncFailed
+	return i18n.Translate("sync.retry")
+}
+`
+	if err := os.WriteFile(filepath.Join(root, "sync.go"), []byte(source), 0644); err != nil {
+		t.Fatal(err)
+	}
+	generated := "// Code generated by the zeitkapsl translations tool from the translations CSV. DO NOT EDIT.\n\npackage i18n\n\nvar x = i18n.T(\"generated.only\")\n"
+	if err := os.WriteFile(filepath.Join(root, "keys_gen.go"), []byte(generated), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tm := NewTranslations("")
+	tm.SetTranslation("core", "sync.done", "en", "Done", "")
+	tm.SetTranslation("core", "sync.failed", "en", "Failed", "")
+
+	scanner := newGoScanner(root, "i18n", coreLookupFunctions, []string{".go"})
+	references, err := scanner.Scan()
+	if err != nil {
+		t.Fatal(err)
+	}
+	resolved := resolveReferences(tm, "core", scanner, references)
+	if _, ok := resolved["sync.failed"]; !ok {
+		t.Errorf("accessor i18n.KeySyncFailed not resolved to sync.failed")
+	}
+
+	missing := FindMissingKeys(tm, "core", resolved)
+	if len(missing) != 1 || missing[0].Key != "sync.retry" {
+		t.Fatalf("missing = %+v, want only sync.retry", missing)
+	}
+	if got, want := missing[0].Location(), filepath.Join(root, "sync.go")+":9"; got != want {
+		t.Errorf("Location() = %q, want %q", got, want)
+	}
+}
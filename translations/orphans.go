@@ -0,0 +1,45 @@
+package main
+
+// hasEnglishSource reports whether a row has an English value. Both halves of a plural
+// pair count as sourced if either of them has one, inheriting rows are always sourced.
+func (tm *Translations) hasEnglishSource(row TranslationRow) bool {
+	if row.Values["en"] != "" || row.Inherits != "" {
+		return true
+	}
+	if !row.IsPlural() {
+		return false
+	}
+	if plural := tm.GetPlural(row.App, row.GetSingularKey()); plural != nil {
+		return plural.One["en"] != "" || plural.Other["en"] != ""
+	}
+	return false
+}
+
+// WithoutOrphans returns a copy that only contains rows with an English source, for exports.
+// Unlike FindOrphans it also drops rows without any value.
+func (tm *Translations) WithoutOrphans() *Translations {
+	result := NewTranslations(tm.BasePath)
+	result.Languages = append(result.Languages, tm.Languages...)
+	for _, row := range tm.Translations {
+		if tm.hasEnglishSource(row) {
+			result.Translations = append(result.Translations, row)
+		}
+	}
+	return result
+}
+
+// FindOrphans returns per language all rows that have a translation but no English source
+func FindOrphans(tm *Translations) map[string][]TranslationRow {
+	result := make(map[string][]TranslationRow)
+	for _, row := range tm.Translations {
+		if tm.hasEnglishSource(row) {
+			continue
+		}
+		for lang, value := range row.Values {
+			if value != "" {
+				result[lang] = append(result[lang], row)
+			}
+		}
+	}
+	return result
+}
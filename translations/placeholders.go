@@ -0,0 +1,22 @@
+package main
+
+import (
+	"regexp"
+	"slices"
+)
+
+// placeholderPattern matches printf style (%s, %1d, %1$s, %@), brace style ({name}) and
+// template literal style (${name}) placeholders
+var placeholderPattern = regexp.MustCompile(`%(?:\d+\$)?[-+ 0#]*\d*(?:\.\d+)?[sdifuxXcb@]|\$?\{[A-Za-z_][A-Za-z0-9_]*\}`)
+
+// extractPlaceholders returns all placeholders of a value in sorted order
+func extractPlaceholders(value string) []string {
+	result := placeholderPattern.FindAllString(value, -1)
+	slices.Sort(result)
+	return result
+}
+
+// placeholdersMatch reports whether both values contain the same placeholders, regardless of order
+func placeholdersMatch(source, target string) bool {
+	return slices.Equal(extractPlaceholders(source), extractPlaceholders(target))
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"strings"
+)
+
+// Plural styles supported by the JSON module
+const (
+	PluralStyleLegacy  = "legacy"  // key.singular / key.plural
+	PluralStyleI18next = "i18next" // key_one / key_few / key_other
+	PluralStyleICU     = "icu"     // {count, plural, one {...} other {...}}
+)
+
+// pluralCategoryOrder is the CLDR order of plural categories
+var pluralCategoryOrder = []string{"zero", "one", "two", "few", "many", "other"}
+
+// cldrPluralCategories lists the cardinal plural categories per language (CLDR)
+var cldrPluralCategories = map[string][]string{
+	"ar": {"zero", "one", "two", "few", "many", "other"},
+	"bg": {"one", "other"},
+	"bs": {"one", "few", "other"},
+	"ca": {"one", "many", "other"},
+	"cs": {"one", "few", "many", "other"},
+	"cy": {"zero", "one", "two", "few", "many", "other"},
+	"da": {"one", "other"},
+	"de": {"one", "other"},
+	"el": {"one", "other"},
+	"en": {"one", "other"},
+	"es": {"one", "many", "other"},
+	"et": {"one", "other"},
+	"fi": {"one", "other"},
+	"fr": {"one", "many", "other"},
+	"ga": {"one", "two", "few", "many", "other"},
+	"hr": {"one", "few", "other"},
+	"hu": {"one", "other"},
+	"is": {"one", "other"},
+	"it": {"one", "many", "other"},
+	"ja": {"other"},
+	"ko": {"other"},
+	"lb": {"one", "other"},
+	"lt": {"one", "few", "many", "other"},
+	"lv": {"zero", "one", "other"},
+	"mk": {"one", "other"},
+	"mt": {"one", "two", "few", "many", "other"},
+	"nb": {"one", "other"},
+	"nl": {"one", "other"},
+	"no": {"one", "other"},
+	"pl": {"one", "few", "many", "other"},
+	"pt": {"one", "many", "other"},
+	"ro": {"one", "few", "other"},
+	"ru": {"one", "few", "many", "other"},
+	"sk": {"one", "few", "many", "other"},
+	"sl": {"one", "two", "few", "other"},
+	"sq": {"one", "other"},
+	"sr": {"one", "few", "other"},
+	"sv": {"one", "other"},
+	"tr": {"one", "other"},
+	"uk": {"one", "few", "many", "other"},
+	"zh": {"other"},
+}
+
+// pluralCategories returns the plural categories a language needs, one/other if unknown
+func pluralCategories(lang string) []string {
+	if categories, ok := cldrPluralCategories[baseLanguage(lang)]; ok {
+		return categories
+	}
+	return []string{"one", "other"}
+}
+
+// pluralFormForCategory maps a plural category onto the singular/plural rows of the model
+func pluralFormForCategory(category, one, other string) string {
+	if category == "one" {
+		return one
+	}
+	return other
+}
+
+// splitI18nextPluralKey splits "photo_count_one" into "photo_count" and "one"
+func splitI18nextPluralKey(key string) (string, string, bool) {
+	for _, category := range pluralCategoryOrder {
+		if strings.HasSuffix(key, "_"+category) && len(key) > len(category)+1 {
+			return key[:len(key)-len(category)-1], category, true
+		}
+	}
+	return key, "", false
+}
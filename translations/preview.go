@@ -0,0 +1,243 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	htmltemplate "html/template"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	texttemplate "text/template"
+)
+
+// DefaultPreviewDirectory is the default output directory for email previews
+const DefaultPreviewDirectory = "email_previews"
+
+// previewSampleData is passed to the templates so fields like {{.Name}} render something
+var previewSampleData = map[string]interface{}{
+	"Name":  "Alex Example",
+	"Email": "alex@example.com",
+	"Date":  "19.10.2026",
+	"Link":  "https://zeitkapsl.eu",
+	"Code":  "123456",
+}
+
+// previewSampleArguments fill the printf placeholders of a string in order
+var previewSampleArguments = []string{"Alex Example", "19.10.2026", "alex@example.com", "3", "https://zeitkapsl.eu"}
+
+var htmlTagPattern = regexp.MustCompile(`(?s)<(script|style)[^>]*>.*?</(script|style)>|<[^>]+>`)
+var blockEndPattern = regexp.MustCompile(`(?i)</(p|div|h[1-6]|li|tr|table)>|<br\s*/?>`)
+var blankLinesPattern = regexp.MustCompile(`\n\s*\n\s*`)
+
+// RenderEmailPreviews renders every email template of the directory in every language into
+// outputDir/<lang>/<template>.html and .txt. Templates look strings up with {{t "key"}},
+// optionally with arguments ({{t "key" .Date}}), missing arguments are filled with sample data.
+// Without templates a generic preview is rendered per key group (e.g. "account_cancelled").
+func RenderEmailPreviews(tm *Translations, app, templateDirectory, outputDirectory string, languages []string) (int, error) {
+	templates, err := filepath.Glob(filepath.Join(templateDirectory, "*.html"))
+	if err != nil {
+		return 0, fmt.Errorf("error listing templates in %s: %v", templateDirectory, err)
+	}
+	if len(templates) == 0 {
+		fmt.Printf("No HTML templates found in %s, rendering a generic preview per key group\n", templateDirectory)
+	}
+
+	rows := tm.GetTranslationsForApp(app)
+	count := 0
+	index := make(map[string][]string)
+
+	for _, lang := range languages {
+		strs := make(map[string]string)
+		for _, row := range rows {
+			strs[row.Key] = row.Values[lang]
+			if strs[row.Key] == "" && isRegion(lang) {
+				strs[row.Key] = row.Values[baseLanguage(lang)]
+			}
+		}
+
+		langDirectory := filepath.Join(outputDirectory, lang)
+		if err := os.MkdirAll(langDirectory, 0755); err != nil {
+			return count, fmt.Errorf("error creating directory %s: %v", langDirectory, err)
+		}
+
+		pages := make(map[string][2]string) // name → html, text
+		if len(templates) == 0 {
+			for name, page := range renderGenericPreviews(strs) {
+				pages[name] = page
+			}
+		}
+		for _, templatePath := range templates {
+			name := strings.TrimSuffix(filepath.Base(templatePath), ".html")
+			htmlContent, textContent, err := renderEmailTemplate(templatePath, strs, lang)
+			if err != nil {
+				fmt.Printf("Warning: Failed rendering %s in %s: %v\n", name, lang, err)
+				continue
+			}
+			pages[name] = [2]string{htmlContent, textContent}
+		}
+
+		for name, page := range pages {
+			htmlPath := filepath.Join(langDirectory, name+".html")
+			if err := os.WriteFile(htmlPath, []byte(page[0]), 0644); err != nil {
+				return count, fmt.Errorf("error writing %s: %v", htmlPath, err)
+			}
+			textPath := filepath.Join(langDirectory, name+".txt")
+			if err := os.WriteFile(textPath, []byte(page[1]), 0644); err != nil {
+				return count, fmt.Errorf("error writing %s: %v", textPath, err)
+			}
+			index[lang] = append(index[lang], name)
+			count++
+		}
+	}
+
+	if err := writePreviewIndex(outputDirectory, index); err != nil {
+		return count, err
+	}
+	return count, nil
+}
+
+// renderEmailTemplate renders an HTML template and its plaintext version. A <name>.txt
+// template next to it is used for the plaintext version, otherwise the HTML is stripped.
+func renderEmailTemplate(templatePath string, strs map[string]string, lang string) (string, string, error) {
+	funcs := map[string]interface{}{
+		"t": func(key string, args ...interface{}) string {
+			value, ok := strs[key]
+			if !ok || value == "" {
+				return "[missing: " + key + "]"
+			}
+			return fillSampleArguments(value, args)
+		},
+	}
+	data := map[string]interface{}{"Lang": lang}
+	for k, v := range previewSampleData {
+		data[k] = v
+	}
+
+	htmlTemplate, err := htmltemplate.New(filepath.Base(templatePath)).Funcs(funcs).Option("missingkey=zero").ParseFiles(templatePath)
+	if err != nil {
+		return "", "", err
+	}
+	var htmlBuf bytes.Buffer
+	if err := htmlTemplate.Execute(&htmlBuf, data); err != nil {
+		return "", "", err
+	}
+
+	textPath := strings.TrimSuffix(templatePath, ".html") + ".txt"
+	if _, err := os.Stat(textPath); err != nil {
+		return htmlBuf.String(), htmlToText(htmlBuf.String()), nil
+	}
+
+	textTemplate, err := texttemplate.New(filepath.Base(textPath)).Funcs(funcs).Option("missingkey=zero").ParseFiles(textPath)
+	if err != nil {
+		return "", "", err
+	}
+	var textBuf bytes.Buffer
+	if err := textTemplate.Execute(&textBuf, data); err != nil {
+		return "", "", err
+	}
+	return htmlBuf.String(), textBuf.String(), nil
+}
+
+// renderGenericPreviews renders one page per key group with the subject as heading and all
+// other strings of the group as paragraphs
+func renderGenericPreviews(strs map[string]string) map[string][2]string {
+	groups := make(map[string][]string)
+	for key := range strs {
+		group := key
+		if i := strings.Index(key, "."); i > 0 {
+			group = key[:i]
+		}
+		groups[group] = append(groups[group], key)
+	}
+
+	result := make(map[string][2]string)
+	for group, keys := range groups {
+		sort.Strings(keys)
+
+		subject := strs[group+".subject"]
+		if subject == "" {
+			subject = group
+		}
+
+		var body, text strings.Builder
+		text.WriteString(fillSampleArguments(subject, nil) + "\n\n")
+		for _, key := range keys {
+			if key == group+".subject" {
+				continue
+			}
+			value := fillSampleArguments(strs[key], nil)
+			if strs[key] == "" {
+				value = "[missing: " + key + "]"
+			}
+			body.WriteString(fmt.Sprintf("    <p title=\"%s\">%s</p>\n", html.EscapeString(key), html.EscapeString(value)))
+			text.WriteString(value + "\n\n")
+		}
+
+		page := fmt.Sprintf("<!DOCTYPE html>\n<html>\n<head><meta charset=\"utf-8\"><title>%s</title></head>\n<body style=\"font-family: sans-serif; max-width: 600px; margin: 2em auto;\">\n    <h1>%s</h1>\n%s</body>\n</html>\n",
+			html.EscapeString(subject), html.EscapeString(subject), body.String())
+		result[group] = [2]string{page, text.String()}
+	}
+	return result
+}
+
+// fillSampleArguments replaces printf placeholders with the given arguments, or sample data
+// where the template passed fewer arguments
+func fillSampleArguments(value string, args []interface{}) string {
+	i := 0
+	return placeholderPattern.ReplaceAllStringFunc(value, func(placeholder string) string {
+		if !strings.HasPrefix(placeholder, "%") {
+			return placeholder
+		}
+		var replacement string
+		if i < len(args) {
+			replacement = fmt.Sprint(args[i])
+		} else {
+			replacement = previewSampleArguments[i%len(previewSampleArguments)]
+		}
+		i++
+		return replacement
+	})
+}
+
+// htmlToText strips all markup from a rendered HTML page
+func htmlToText(content string) string {
+	content = blockEndPattern.ReplaceAllString(content, "\n\n")
+	content = html.UnescapeString(htmlTagPattern.ReplaceAllString(content, ""))
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	return strings.TrimSpace(blankLinesPattern.ReplaceAllString(strings.Join(lines, "\n"), "\n\n")) + "\n"
+}
+
+// writePreviewIndex writes an index.html linking all rendered previews
+func writePreviewIndex(outputDirectory string, index map[string][]string) error {
+	langs := make([]string, 0, len(index))
+	for lang := range index {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+
+	var sb strings.Builder
+	sb.WriteString("<!DOCTYPE html>\n<html>\n<head><meta charset=\"utf-8\"><title>Email previews</title></head>\n<body style=\"font-family: sans-serif;\">\n")
+	for _, lang := range langs {
+		names := index[lang]
+		sort.Strings(names)
+		sb.WriteString(fmt.Sprintf("<h2>%s</h2>\n<ul>\n", html.EscapeString(lang)))
+		for _, name := range names {
+			link := html.EscapeString(lang + "/" + name)
+			sb.WriteString(fmt.Sprintf("  <li><a href=\"%s.html\">%s</a> (<a href=\"%s.txt\">text</a>)</li>\n", link, html.EscapeString(name), link))
+		}
+		sb.WriteString("</ul>\n")
+	}
+	sb.WriteString("</body>\n</html>\n")
+
+	indexPath := filepath.Join(outputDirectory, "index.html")
+	if err := os.WriteFile(indexPath, []byte(sb.String()), 0644); err != nil {
+		return fmt.Errorf("error writing %s: %v", indexPath, err)
+	}
+	return nil
+}
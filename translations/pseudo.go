@@ -0,0 +1,185 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Pseudo locales generated from English, named like the Android pseudo locales
+const (
+	PseudoLocaleAccented = "en_XA" // accented and expanded text
+	PseudoLocaleBidi     = "ar_XB" // mirrored right-to-left text
+)
+
+// isPseudoLocale reports whether a locale is one of the generated pseudo locales, which are
+// never imported
+func isPseudoLocale(lang string) bool {
+	lang = strings.ReplaceAll(lang, "-", "_")
+	return lang == PseudoLocaleAccented || lang == PseudoLocaleBidi
+}
+
+// removeStalePseudoLocales removes the files an earlier export with pseudo locales left
+// behind, if the exported translations have none. files returns the files of a locale.
+func removeStalePseudoLocales(tm *Translations, files func(lang string) []string) error {
+	for _, lang := range []string{PseudoLocaleAccented, PseudoLocaleBidi} {
+		if slices.Contains(tm.Languages, lang) {
+			continue
+		}
+		for _, file := range files(lang) {
+			err := os.Remove(file)
+			if os.IsNotExist(err) {
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("error removing pseudo locale file %s: %v", file, err)
+			}
+			fmt.Printf("Removed pseudo locale file %s\n", file)
+		}
+	}
+	return nil
+}
+
+var pseudoAccents = map[rune]rune{
+	'a': 'á', 'b': 'ƀ', 'c': 'ç', 'd': 'ð', 'e': 'é', 'f': 'ƒ', 'g': 'ĝ', 'h': 'ĥ', 'i': 'î', 'j': 'ĵ',
+	'k': 'ķ', 'l': 'ļ', 'm': 'ɱ', 'n': 'ñ', 'o': 'ö', 'p': 'þ', 'q': 'ǫ', 'r': 'ŕ', 's': 'š', 't': 'ţ',
+	'u': 'û', 'v': 'ṽ', 'w': 'ŵ', 'x': 'ẋ', 'y': 'ý', 'z': 'ž',
+	'A': 'Å', 'B': 'Ɓ', 'C': 'Ç', 'D': 'Ð', 'E': 'É', 'F': 'Ƒ', 'G': 'Ĝ', 'H': 'Ĥ', 'I': 'Î', 'J': 'Ĵ',
+	'K': 'Ķ', 'L': 'Ļ', 'M': 'Ṁ', 'N': 'Ñ', 'O': 'Ö', 'P': 'Þ', 'Q': 'Ǫ', 'R': 'Ŕ', 'S': 'Š', 'T': 'Ţ',
+	'U': 'Û', 'V': 'Ṽ', 'W': 'Ŵ', 'X': 'Ẋ', 'Y': 'Ý', 'Z': 'Ž',
+}
+
+var pseudoExpansionWords = []string{"one", "two", "three", "four", "five", "six", "seven", "eight", "nine", "ten"}
+
+// pseudoProtectedPattern matches the parts of a plain text that are kept as they are:
+// placeholders, HTML tags and entities, and backslash escapes
+var pseudoProtectedPattern = regexp.MustCompile(placeholderPattern.String() + `|<[^>]+>|&[A-Za-z0-9#]+;|\\.`)
+
+// WithPseudoLocales returns a copy with the pseudo locales generated from the English values.
+// The copy is meant for exports only and must never be saved to the CSV.
+func (tm *Translations) WithPseudoLocales() *Translations {
+	result := NewTranslations(tm.BasePath)
+	result.Languages = append(result.Languages, tm.Languages...)
+	result.EnsureLanguage(PseudoLocaleAccented)
+	result.EnsureLanguage(PseudoLocaleBidi)
+
+	for _, row := range tm.Translations {
+		pseudo := row
+		pseudo.Values = make(map[string]string, len(row.Values)+2)
+		for lang, value := range row.Values {
+			pseudo.Values[lang] = value
+		}
+		if source := row.Values["en"]; source != "" {
+			pseudo.Values[PseudoLocaleAccented] = PseudoLocalize(source, PseudoLocaleAccented)
+			pseudo.Values[PseudoLocaleBidi] = PseudoLocalize(source, PseudoLocaleBidi)
+		}
+		result.Translations = append(result.Translations, pseudo)
+	}
+	return result
+}
+
+// PseudoLocalize transforms an English text for a pseudo locale. Placeholders, markup and
+// the structure of ICU messages are kept intact.
+func PseudoLocalize(text, locale string) string {
+	transform := pseudoAccent
+	if locale == PseudoLocaleBidi {
+		transform = pseudoBidi
+	}
+
+	var result string
+	if nodes, err := ParseICU(text); err == nil && hasICUStructure(nodes) {
+		result = pseudoICU(nodes, transform)
+	} else {
+		result = mapPlainText(text, pseudoProtectedPattern, transform)
+	}
+
+	if locale == PseudoLocaleAccented {
+		return "[" + result + pseudoExpansion(text) + "]"
+	}
+	return result
+}
+
+// mapPlainText transforms the text between the protected parts of a plain text
+func mapPlainText(text string, protected *regexp.Regexp, transform func(string) string) string {
+	var sb strings.Builder
+	last := 0
+	for _, match := range protected.FindAllStringIndex(text, -1) {
+		sb.WriteString(transform(text[last:match[0]]))
+		sb.WriteString(text[match[0]:match[1]])
+		last = match[1]
+	}
+	sb.WriteString(transform(text[last:]))
+	return sb.String()
+}
+
+// pseudoICU renders an ICU message with all literal texts transformed
+func pseudoICU(nodes []ICUNode, transform func(string) string) string {
+	var sb strings.Builder
+	for _, node := range nodes {
+		switch {
+		case node.Kind == ICUText:
+			sb.WriteString(quoteICU(mapPlainText(node.Text, pseudoProtectedPattern, transform)))
+		case node.Kind == ICUPound:
+			sb.WriteString("#")
+		case len(node.Options) == 0:
+			sb.WriteString(node.Raw)
+		default:
+			sb.WriteString("{" + node.Arg + ", " + node.Type + ",")
+			if node.Offset != 0 {
+				sb.WriteString(" offset:" + strconv.Itoa(node.Offset))
+			}
+			for _, option := range node.Options {
+				sb.WriteString(" " + option.Selector + " {" + pseudoICU(option.Message, transform) + "}")
+			}
+			sb.WriteString("}")
+		}
+	}
+	return sb.String()
+}
+
+// pseudoAccent replaces ASCII letters with accented look-alikes
+func pseudoAccent(text string) string {
+	return strings.Map(func(r rune) rune {
+		if accented, ok := pseudoAccents[r]; ok {
+			return accented
+		}
+		return r
+	}, text)
+}
+
+// pseudoBidi wraps every word in right-to-left override marks, as the Android ar-XB locale does
+func pseudoBidi(text string) string {
+	var sb strings.Builder
+	word := strings.Builder{}
+	flush := func() {
+		if word.Len() > 0 {
+			sb.WriteString("\u200f\u202e" + word.String() + "\u202c\u200f")
+			word.Reset()
+		}
+	}
+	for _, r := range text {
+		if r == ' ' || r == '\n' || r == '\t' {
+			flush()
+			sb.WriteRune(r)
+			continue
+		}
+		word.WriteRune(r)
+	}
+	flush()
+	return sb.String()
+}
+
+// pseudoExpansion returns padding words that make a text about 30% longer, so layouts are
+// tested against languages with longer texts
+func pseudoExpansion(text string) string {
+	target := (utf8.RuneCountInString(text) + 2) / 3
+	var sb strings.Builder
+	for i := 0; sb.Len() == 0 || utf8.RuneCountInString(sb.String()) < target; i++ {
+		sb.WriteString(" " + pseudoExpansionWords[i%len(pseudoExpansionWords)])
+	}
+	return sb.String()
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultQualityReportFile is the default filename for the quality check report
+const DefaultQualityReportFile = "quality_report.csv"
+
+// QualityOptions configures which cells are checked and when a result counts as suspicious
+type QualityOptions struct {
+	Apps           []string
+	Languages      []string
+	All            bool    // check every translated cell, not only machine translations
+	MinSimilarity  float64 // back-translations below this similarity are suspicious
+	MinLengthRatio float64
+	MaxLengthRatio float64
+}
+
+// QualityResult is the outcome of checking a single translated cell
+type QualityResult struct {
+	App             string
+	Key             string
+	Lang            string
+	Provider        string
+	Source          string
+	Translation     string
+	BackTranslation string
+	Similarity      float64
+	LengthRatio     float64
+	Issues          []string
+	Score           float64 // higher is more suspicious
+}
+
+// CheckTranslationQuality back-translates translated cells to English and returns the suspicious
+// ones, most suspicious first. Machine translations are detected through the translation memory.
+func CheckTranslationQuality(tm *Translations, service TranslationService, memory *TranslationMemory, opts QualityOptions) ([]QualityResult, error) {
+	if service == nil {
+		return nil, fmt.Errorf("no translation service configured")
+	}
+
+	sourceLang := "en"
+	results := make([]QualityResult, 0)
+	checked := 0
+
+	fmt.Printf("Using %s for back-translation to English\n", service.Name())
+
+	for _, row := range tm.Translations {
+		source := row.Values[sourceLang]
+		if source == "" {
+			continue
+		}
+		if len(opts.Apps) > 0 && !slices.Contains(opts.Apps, row.App) {
+			continue
+		}
+
+		for _, lang := range tm.Languages {
+			translation := row.Values[lang]
+			if lang == sourceLang || translation == "" {
+				continue
+			}
+			if len(opts.Languages) > 0 && !slices.Contains(opts.Languages, lang) {
+				continue
+			}
+
+			provider := ProviderCSV
+			if memory != nil {
+				if entry := memory.CellEntry(row.App, row.Key, lang, translation); entry != nil {
+					provider = entry.Provider
+				}
+			}
+			if !opts.All && (provider == ProviderCSV || provider == ProviderReviewed) {
+				continue
+			}
+
+			throttle(service, checked)
+			backTranslation, err := service.Translate(translation, baseLanguage(lang), sourceLang)
+			checked++
+			if err != nil {
+				fmt.Printf("Error back-translating %s:%s (%s): %v\n", row.App, row.Key, lang, err)
+				continue
+			}
+
+			result := QualityResult{
+				App:             row.App,
+				Key:             row.Key,
+				Lang:            lang,
+				Provider:        provider,
+				Source:          source,
+				Translation:     translation,
+				BackTranslation: backTranslation,
+				Similarity:      textSimilarity(source, backTranslation),
+				LengthRatio:     float64(utf8.RuneCountInString(translation)) / float64(utf8.RuneCountInString(source)),
+			}
+			result.Score = 1 - result.Similarity
+
+			if result.Similarity < opts.MinSimilarity {
+				result.Issues = append(result.Issues, fmt.Sprintf("back-translation similarity %.0f%%", result.Similarity*100))
+			}
+			if result.LengthRatio < opts.MinLengthRatio || result.LengthRatio > opts.MaxLengthRatio {
+				result.Issues = append(result.Issues, fmt.Sprintf("length ratio %.2f", result.LengthRatio))
+				result.Score += 0.5
+			}
+			if !placeholdersMatch(source, translation) {
+				result.Issues = append(result.Issues, fmt.Sprintf("placeholders %v, expected %v", extractPlaceholders(translation), extractPlaceholders(source)))
+				result.Score += 1
+			}
+
+			if len(result.Issues) > 0 {
+				results = append(results, result)
+			}
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+
+	fmt.Printf("Checked %d translations, %d look suspicious\n", checked, len(results))
+	return results, nil
+}
+
+// SaveQualityReport writes the ranked results as CSV for human review
+func SaveQualityReport(results []QualityResult, filename string) error {
+	if filename == "" {
+		filename = DefaultQualityReportFile
+	}
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("error creating report file: %v", err)
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	writer.Comma = ';'
+	defer writer.Flush()
+
+	header := []string{"rank", "score", "app", "key", "lang", "provider", "en", "translation", "back_translation", "similarity", "length_ratio", "issues"}
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("error writing report header: %v", err)
+	}
+
+	for i, r := range results {
+		record := []string{
+			fmt.Sprint(i + 1),
+			fmt.Sprintf("%.2f", r.Score),
+			r.App,
+			r.Key,
+			r.Lang,
+			r.Provider,
+			r.Source,
+			r.Translation,
+			r.BackTranslation,
+			fmt.Sprintf("%.2f", r.Similarity),
+			fmt.Sprintf("%.2f", r.LengthRatio),
+			strings.Join(r.Issues, ", "),
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("error writing report record: %v", err)
+		}
+	}
+
+	fmt.Printf("Saved quality report to %s\n", filename)
+	return nil
+}
+
+// textSimilarity averages the character similarity and the word overlap of two texts,
+// so reordered but otherwise equal sentences still score high
+func textSimilarity(a, b string) float64 {
+	return (similarity(a, b) + wordOverlap(a, b)) / 2
+}
+
+// wordOverlap returns the Jaccard index of the lowercase words of both texts
+func wordOverlap(a, b string) float64 {
+	wordsA := wordSet(a)
+	wordsB := wordSet(b)
+	if len(wordsA) == 0 && len(wordsB) == 0 {
+		return 1
+	}
+
+	common := 0
+	for w := range wordsA {
+		if wordsB[w] {
+			common++
+		}
+	}
+	return float64(common) / float64(len(wordsA)+len(wordsB)-common)
+}
+
+func wordSet(text string) map[string]bool {
+	result := make(map[string]bool)
+	for _, w := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		result[w] = true
+	}
+	return result
+}
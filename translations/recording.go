@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Recording modes of the RecordingTransport
+const (
+	RecordMode = "record"
+	ReplayMode = "replay"
+)
+
+// RecordingTransport saves HTTP responses to fixture files (record) or serves them from
+// fixture files without any network access (replay). Fixtures are keyed by method, URL and
+// body, request headers such as API keys are never written.
+type RecordingTransport struct {
+	Mode string
+	Dir  string
+	Next http.RoundTripper // used in record mode, http.DefaultTransport if nil
+}
+
+// fixture is the on-disk representation of a recorded exchange
+type fixture struct {
+	Method       string      `json:"method"`
+	URL          string      `json:"url"`
+	RequestBody  string      `json:"request_body"`
+	StatusCode   int         `json:"status_code"`
+	Header       http.Header `json:"header"`
+	ResponseBody string      `json:"response_body"`
+}
+
+// NewRecordingClient returns an HTTP client that records to or replays from dir
+func NewRecordingClient(mode, dir string) (*http.Client, error) {
+	if mode != RecordMode && mode != ReplayMode {
+		return nil, fmt.Errorf("unknown recording mode: %s. Use '%s' or '%s'", mode, RecordMode, ReplayMode)
+	}
+	if mode == RecordMode {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, fmt.Errorf("error creating fixture directory %s: %v", dir, err)
+		}
+	}
+	return &http.Client{
+		Timeout:   30 * time.Second,
+		Transport: &RecordingTransport{Mode: mode, Dir: dir},
+	}, nil
+}
+
+func (t *RecordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		if err != nil {
+			return nil, fmt.Errorf("error reading request body: %v", err)
+		}
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	filename := filepath.Join(t.Dir, fixtureName(req.Method, req.URL.String(), body))
+
+	if t.Mode == ReplayMode {
+		data, err := os.ReadFile(filename)
+		if err != nil {
+			return nil, fmt.Errorf("no fixture for %s %s: %v", req.Method, req.URL, err)
+		}
+		var f fixture
+		if err := json.Unmarshal(data, &f); err != nil {
+			return nil, fmt.Errorf("error parsing fixture %s: %v", filename, err)
+		}
+		return &http.Response{
+			StatusCode: f.StatusCode,
+			Status:     fmt.Sprintf("%d %s", f.StatusCode, http.StatusText(f.StatusCode)),
+			Header:     f.Header,
+			Body:       io.NopCloser(bytes.NewReader([]byte(f.ResponseBody))),
+			Request:    req,
+		}, nil
+	}
+
+	next := t.Next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	resp, err := next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response body: %v", err)
+	}
+
+	data, err := json.MarshalIndent(fixture{
+		Method:       req.Method,
+		URL:          req.URL.String(),
+		RequestBody:  string(body),
+		StatusCode:   resp.StatusCode,
+		Header:       resp.Header,
+		ResponseBody: string(respBody),
+	}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("error encoding fixture: %v", err)
+	}
+	if err := os.WriteFile(filename, append(data, '\n'), 0644); err != nil {
+		return nil, fmt.Errorf("error writing fixture %s: %v", filename, err)
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	return resp, nil
+}
+
+// fixtureName derives a stable filename from the request
+func fixtureName(method, url string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method + " " + url + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))[:16] + ".json"
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecordingTransportRecordAndReplay(t *testing.T) {
+	requests := 0
+	server := newDeepLServer(t, map[string]string{"DE:Delete": "Löschen"}, &requests)
+	dir := t.TempDir()
+
+	record, err := NewRecordingClient(RecordMode, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	deepl := &DeepLTranslator{APIKey: "test-key", Endpoint: server.URL + "/v2/translate", Client: record}
+	if got, err := deepl.Translate("Delete", "en", "de"); err != nil || got != "Löschen" {
+		t.Fatalf("recording: got %q, %v", got, err)
+	}
+	server.Close()
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(files) != 1 {
+		t.Fatalf("recorded %d fixtures, want 1", len(files))
+	}
+	data, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "test-key") {
+		t.Errorf("fixture contains the API key")
+	}
+
+	replay, err := NewRecordingClient(ReplayMode, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	deepl.Client = replay
+	if got, err := deepl.Translate("Delete", "en", "de"); err != nil || got != "Löschen" {
+		t.Errorf("replaying: got %q, %v", got, err)
+	}
+	if requests != 1 {
+		t.Errorf("requests = %d, want 1", requests)
+	}
+}
+
+func TestRecordingTransportReplayFixture(t *testing.T) {
+	t.Setenv("DEEPL_API_KEY", "")
+	t.Setenv("DEEPL_ENDPOINT", "")
+
+	client, err := NewRecordingClient(ReplayMode, filepath.Join("testdata", "replay"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	service, err := NewTranslationService("deepl", client)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := service.Translate("Delete", "en", "de")
+	if err != nil {
+		t.Fatalf("Translate: %v", err)
+	}
+	if got != "Löschen" {
+		t.Errorf("Translate = %q, want %q", got, "Löschen")
+	}
+
+	if _, err := service.Translate("Cancel", "en", "de"); err == nil || !strings.Contains(err.Error(), "no fixture") {
+		t.Errorf("Translate without a fixture: err = %v, want a missing fixture error", err)
+	}
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// MoveKey moves a key to another app and/or key, renaming is a move within the same app.
+// Plural pairs are moved as a whole, given either their base key or one of their halves.
+// Values, comments and metadata are kept and links of rows inheriting from the moved key
+// follow it. It returns the moved row keys, old → new.
+func (tm *Translations) MoveKey(fromApp, oldKey, toApp, newKey string) (map[string]string, error) {
+	if newKey == "" {
+		newKey = oldKey
+	}
+	if fromApp == toApp && oldKey == newKey {
+		return nil, fmt.Errorf("%s:%s would be moved onto itself", fromApp, oldKey)
+	}
+
+	moved := make(map[string]string)
+	if tm.GetRow(fromApp, oldKey) != nil && !(TranslationRow{Key: oldKey}).IsPlural() {
+		moved[oldKey] = newKey
+	} else {
+		oldBase := strings.TrimSuffix(strings.TrimSuffix(oldKey, ".singular"), ".plural")
+		newBase := strings.TrimSuffix(strings.TrimSuffix(newKey, ".singular"), ".plural")
+		for _, suffix := range []string{".singular", ".plural"} {
+			if tm.GetRow(fromApp, oldBase+suffix) != nil {
+				moved[oldBase+suffix] = newBase + suffix
+			}
+		}
+	}
+	if len(moved) == 0 {
+		return nil, fmt.Errorf("key %s:%s not found", fromApp, oldKey)
+	}
+
+	for _, target := range moved {
+		if tm.GetRow(toApp, target) != nil {
+			return nil, fmt.Errorf("key %s:%s already exists", toApp, target)
+		}
+	}
+
+	// Plural pairs are linked by their base key
+	links := make(map[string]string)
+	for oldRowKey, newRowKey := range moved {
+		links[TranslationRow{Key: oldRowKey}.GetSingularKey()] = TranslationRow{Key: newRowKey}.GetSingularKey()
+	}
+
+	for i, row := range tm.Translations {
+		if target, ok := moved[row.Key]; ok && row.App == fromApp {
+			tm.Translations[i].App = toApp
+			tm.Translations[i].Key = target
+		}
+		if row.Inherits == "" {
+			continue
+		}
+		if linkApp, linkKey := parseLink(row.Inherits); linkApp == fromApp && links[linkKey] != "" {
+			tm.Translations[i].Inherits = toApp + ":" + links[linkKey]
+		}
+	}
+	tm.Sort()
+	return moved, nil
+}
+
+// MoveKey updates the entries of moved rows so their provenance and states stay attached
+func (m *TranslationMemory) MoveKey(fromApp, toApp string, moved map[string]string) int {
+	count := 0
+	for i, entry := range m.Entries {
+		if target, ok := moved[entry.Key]; ok && entry.App == fromApp {
+			m.Entries[i].App = toApp
+			m.Entries[i].Key = target
+			count++
+		}
+	}
+	return count
+}
+
+// RewriteKeys replaces references to the moved keys in the scanned source files. Generated
+// files are skipped, the accessors have to be generated again. It returns the number of
+// replaced references.
+func (s *SourceScanner) RewriteKeys(moved map[string]string) (int, error) {
+	keys := make(map[string]string)
+	for oldKey, newKey := range moved {
+		keys[oldKey] = newKey
+		oldRow, newRow := TranslationRow{Key: oldKey}, TranslationRow{Key: newKey}
+		if oldRow.IsPlural() {
+			keys[oldRow.GetSingularKey()] = newRow.GetSingularKey()
+		}
+	}
+
+	identifiers := make(map[string]string)
+	if s.Identifiers != nil {
+		for oldKey, newKey := range keys {
+			oldNames, newNames := s.Identifiers(oldKey), s.Identifiers(newKey)
+			for i := range oldNames {
+				identifiers[oldNames[i]] = newNames[i]
+			}
+		}
+	}
+
+	files := make([]string, 0)
+	if err := s.walk(func(filePath string) error {
+		files = append(files, filePath)
+		return nil
+	}); err != nil {
+		return 0, err
+	}
+	sort.Strings(files)
+
+	total := 0
+	for _, filePath := range files {
+		data, err := os.ReadFile(filePath)
+		if err != nil {
+			return total, fmt.Errorf("error reading %s: %v", filePath, err)
+		}
+		content := string(data)
+		if isGeneratedSource(content) {
+			continue
+		}
+
+		count := 0
+		for _, pattern := range append(append([]*regexp.Regexp{}, s.KeyPatterns...), s.PluralPatterns...) {
+			content, count = replaceSubmatches(content, pattern, keys, count)
+		}
+		for _, pattern := range s.AccessorPatterns {
+			content, count = replaceSubmatches(content, pattern, identifiers, count)
+		}
+		if count == 0 {
+			continue
+		}
+
+		if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+			return total, fmt.Errorf("error writing %s: %v", filePath, err)
+		}
+		fmt.Printf("Rewrote %d references in %s\n", count, filePath)
+		total += count
+	}
+	return total, nil
+}
+
+// replaceSubmatches replaces the first group of every match that is a key of replacements
+func replaceSubmatches(content string, pattern *regexp.Regexp, replacements map[string]string, count int) (string, int) {
+	var sb strings.Builder
+	last := 0
+	for _, match := range pattern.FindAllStringSubmatchIndex(content, -1) {
+		start, end := match[2], match[3]
+		replacement, ok := replacements[content[start:end]]
+		if !ok {
+			continue
+		}
+		sb.WriteString(content[last:start])
+		sb.WriteString(replacement)
+		last = end
+		count++
+	}
+	sb.WriteString(content[last:])
+	return sb.String(), count
+}
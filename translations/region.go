@@ -0,0 +1,274 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultRegionDictionaryFile is the default filename for the regional substitution dictionary
+const DefaultRegionDictionaryFile = "region_dictionary.json"
+
+// RegionDictionary maps a region (e.g. de_AT) to word substitutions applied to the base language value
+type RegionDictionary map[string]map[string]string
+
+// RegionAdapter adapts a text of a base language to a regional variant
+type RegionAdapter interface {
+	Adapt(text, baseLang, region string) (string, error)
+	Name() string
+}
+
+// OpenAIAdapter implements RegionAdapter using an OpenAI compatible chat completions API
+type OpenAIAdapter struct {
+	APIKey   string
+	Model    string
+	Endpoint string
+	Client   *http.Client // optional, e.g. with a recording transport
+}
+
+func (o *OpenAIAdapter) Name() string {
+	return "OpenAI " + o.Model
+}
+
+func (o *OpenAIAdapter) Adapt(text, baseLang, region string) (string, error) {
+	if o.APIKey == "" {
+		return "", fmt.Errorf("OpenAI API key not set. Set OPENAI_API_KEY environment variable")
+	}
+
+	prompt := fmt.Sprintf("Adapt the following %s text to the regional variant %s. "+
+		"Only change words or spellings that differ in that region and keep everything else as it is. "+
+		"Keep placeholders like %%s, %%1d or {name} and any markup unchanged. "+
+		"Answer with the adapted text only.", baseLang, region)
+	if hint := icuPromptHint(text); hint != "" {
+		prompt += " " + hint
+	}
+	prompt += "\n\n" + text
+
+	requestBody, err := json.Marshal(map[string]interface{}{
+		"model":       o.Model,
+		"temperature": 0,
+		"messages": []map[string]string{
+			{"role": "user", "content": prompt},
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("error creating request: %v", err)
+	}
+
+	req, err := http.NewRequest("POST", o.Endpoint, bytes.NewBuffer(requestBody))
+	if err != nil {
+		return "", fmt.Errorf("error creating request: %v", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+o.APIKey)
+
+	resp, err := httpClient(o.Client, 30*time.Second).Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error sending request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("error reading response: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var result struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", fmt.Errorf("error parsing response: %v", err)
+	}
+
+	if len(result.Choices) == 0 {
+		return "", fmt.Errorf("no adaptation returned")
+	}
+
+	adapted := strings.TrimSpace(result.Choices[0].Message.Content)
+	if errors, _ := ValidateICU(text, adapted, region); len(errors) > 0 {
+		return "", fmt.Errorf("adaptation broke the message: %s", strings.Join(errors, ", "))
+	}
+	return adapted, nil
+}
+
+// GetRegionAdapter returns an LLM adapter based on environment variables
+func GetRegionAdapter() RegionAdapter {
+	apiKey := os.Getenv("OPENAI_API_KEY")
+	if apiKey == "" {
+		return nil
+	}
+
+	model := os.Getenv("OPENAI_MODEL")
+	if model == "" {
+		model = "gpt-4o-mini"
+	}
+	endpoint := os.Getenv("OPENAI_ENDPOINT")
+	if endpoint == "" {
+		endpoint = "https://api.openai.com/v1/chat/completions"
+	}
+
+	return &OpenAIAdapter{APIKey: apiKey, Model: model, Endpoint: endpoint}
+}
+
+// LoadRegionDictionary loads the substitution dictionary, a missing file yields an empty dictionary
+func LoadRegionDictionary(filename string) (RegionDictionary, error) {
+	if filename == "" {
+		filename = DefaultRegionDictionaryFile
+	}
+
+	dict := make(RegionDictionary)
+	data, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return dict, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading region dictionary %s: %v", filename, err)
+	}
+	if err := json.Unmarshal(data, &dict); err != nil {
+		return nil, fmt.Errorf("error parsing region dictionary %s: %v", filename, err)
+	}
+	return dict, nil
+}
+
+// Apply replaces all whole-word occurrences of the region's dictionary entries, longest entries first
+func (d RegionDictionary) Apply(region, text string) string {
+	substitutions := d[region]
+	if len(substitutions) == 0 {
+		return text
+	}
+
+	words := make([]string, 0, len(substitutions))
+	for word := range substitutions {
+		words = append(words, word)
+	}
+	sort.Slice(words, func(i, j int) bool {
+		if len(words[i]) != len(words[j]) {
+			return len(words[i]) > len(words[j])
+		}
+		return words[i] < words[j]
+	})
+
+	for _, word := range words {
+		text = replaceWord(text, word, substitutions[word])
+	}
+	return text
+}
+
+// replaceWord replaces occurrences of word that are not part of a longer word
+func replaceWord(text, word, replacement string) string {
+	if word == "" {
+		return text
+	}
+
+	// Boundaries are checked against the whole text, a skipped match is not a word boundary
+	var sb strings.Builder
+	pos := 0
+	for {
+		idx := strings.Index(text[pos:], word)
+		if idx < 0 {
+			sb.WriteString(text[pos:])
+			return sb.String()
+		}
+		start, end := pos+idx, pos+idx+len(word)
+
+		before, _ := utf8.DecodeLastRuneInString(text[:start])
+		after, _ := utf8.DecodeRuneInString(text[end:])
+		sb.WriteString(text[pos:start])
+		if (start > 0 && unicode.IsLetter(before)) || (end < len(text) && unicode.IsLetter(after)) {
+			sb.WriteString(word)
+		} else {
+			sb.WriteString(replacement)
+		}
+		pos = end
+	}
+}
+
+// baseLanguage returns the language part of a regional code (de_AT and de-AT → de)
+func baseLanguage(lang string) string {
+	if i := strings.IndexAny(lang, "_-"); i > 0 {
+		return lang[:i]
+	}
+	return lang
+}
+
+// isRegion reports whether a language code contains a region
+func isRegion(lang string) bool {
+	return strings.ContainsAny(lang, "_-")
+}
+
+// AdaptRegions fills empty regional cells from the base language value using the dictionary
+// and the optional adapter. A value is only stored when it differs from the base language.
+func AdaptRegions(tm *Translations, dict RegionDictionary, adapter RegionAdapter, opts AutoTranslateOptions) (int, error) {
+	regions := []string{}
+	for _, lang := range tm.Languages {
+		if !isRegion(lang) {
+			continue
+		}
+		if len(opts.Languages) > 0 && !slices.Contains(opts.Languages, lang) {
+			continue
+		}
+		regions = append(regions, lang)
+	}
+
+	if len(regions) == 0 {
+		return 0, fmt.Errorf("no regions to adapt")
+	}
+
+	fmt.Printf("Adapting regions: %s\n", strings.Join(regions, ", "))
+	if adapter != nil {
+		fmt.Printf("Using %s for regional adaptation\n", adapter.Name())
+	}
+
+	count := 0
+	for i, row := range tm.Translations {
+		if !opts.matchesRow(row) {
+			continue
+		}
+		for _, region := range regions {
+			base := row.Values[baseLanguage(region)]
+			if base == "" || row.Values[region] != "" {
+				continue
+			}
+
+			adapted := dict.Apply(region, base)
+			if adapter != nil {
+				result, err := adapter.Adapt(adapted, baseLanguage(region), region)
+				if err != nil {
+					fmt.Printf("Error adapting %s:%s to %s: %v\n", row.App, row.Key, region, err)
+					continue
+				}
+				adapted = result
+			}
+
+			if adapted == base {
+				continue
+			}
+
+			if tm.Translations[i].Values == nil {
+				tm.Translations[i].Values = make(map[string]string)
+			}
+			tm.Translations[i].Values[region] = adapted
+			count++
+			fmt.Printf("Adapting [%s→%s]: %s -> %s\n", baseLanguage(region), region, base, adapted)
+		}
+	}
+	return count, nil
+}
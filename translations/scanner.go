@@ -0,0 +1,272 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// skippedSourceDirectories are never scanned for key references
+var skippedSourceDirectories = []string{".git", "node_modules", "build", ".build", ".gradle", ".svelte-kit", "Pods", "DerivedData", "vendor"}
+
+// templateKeyPattern finds {{t "key"}} in the email templates, see RenderEmailPreviews
+var templateKeyPattern = regexp.MustCompile(`\{\{-?\s*t\s+"([^"\\]+)"`)
+
+// SourceScanner finds references to translation keys in the source code of an app
+type SourceScanner struct {
+	Roots       []string
+	Extensions  []string
+	ExcludeDirs []string // glob patterns matched against directory names
+	// KeyPatterns capture a key in their first group
+	KeyPatterns []*regexp.Regexp
+	// PluralPatterns capture the base key of a plural in their first group
+	PluralPatterns []*regexp.Regexp
+	// AccessorPatterns capture an identifier of the generated accessors in their first group
+	AccessorPatterns []*regexp.Regexp
+	// Identifiers returns the accessor identifiers generated for a key
+	Identifiers func(key string) []string
+}
+
+// KeyReference is a key or accessor found in a source file
+type KeyReference struct {
+	Key        string
+	Identifier string
+	Plural     bool
+	File       string
+	Line       int
+}
+
+// Scan walks all roots and returns the references in file and line order. Generated files
+// are skipped, they reference every key.
+func (s *SourceScanner) Scan() ([]KeyReference, error) {
+	result := make([]KeyReference, 0)
+	err := s.walk(func(filePath string) error {
+		references, err := s.scanFile(filePath)
+		if err != nil {
+			return err
+		}
+		result = append(result, references...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// walk calls fn for every source file below the roots
+func (s *SourceScanner) walk(fn func(filePath string) error) error {
+	for _, root := range s.Roots {
+		if _, err := os.Stat(root); os.IsNotExist(err) {
+			fmt.Printf("Warning: source directory %s not found, skipping\n", root)
+			continue
+		}
+
+		err := filepath.WalkDir(root, func(filePath string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				if filePath != root && s.excluded(d.Name()) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !slices.Contains(s.Extensions, filepath.Ext(filePath)) {
+				return nil
+			}
+			return fn(filePath)
+		})
+		if err != nil {
+			return fmt.Errorf("error scanning %s: %v", root, err)
+		}
+	}
+	return nil
+}
+
+func (s *SourceScanner) excluded(name string) bool {
+	if slices.Contains(skippedSourceDirectories, name) {
+		return true
+	}
+	for _, pattern := range s.ExcludeDirs {
+		if matched, _ := filepath.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *SourceScanner) scanFile(filePath string) ([]KeyReference, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %v", filePath, err)
+	}
+	defer file.Close()
+
+	result := make([]KeyReference, 0)
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := scanner.Text()
+		if line == 1 && isGeneratedSource(text) {
+			return nil, nil
+		}
+		for _, pattern := range s.KeyPatterns {
+			for _, match := range pattern.FindAllStringSubmatch(text, -1) {
+				result = append(result, KeyReference{Key: match[1], File: filePath, Line: line})
+			}
+		}
+		for _, pattern := range s.PluralPatterns {
+			for _, match := range pattern.FindAllStringSubmatch(text, -1) {
+				result = append(result, KeyReference{Key: match[1], Plural: true, File: filePath, Line: line})
+			}
+		}
+		for _, pattern := range s.AccessorPatterns {
+			for _, match := range pattern.FindAllStringSubmatch(text, -1) {
+				result = append(result, KeyReference{Identifier: match[1], File: filePath, Line: line})
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading %s: %v", filePath, err)
+	}
+	return result, nil
+}
+
+// isGeneratedSource reports whether the first line marks a file as generated
+func isGeneratedSource(content string) bool {
+	firstLine, _, _ := strings.Cut(content, "\n")
+	return strings.Contains(firstLine, "Code generated") && strings.Contains(firstLine, "DO NOT EDIT")
+}
+
+// resolveReferences groups the references by key. Accessor identifiers are resolved to the
+// keys of the app, unknown identifiers (e.g. helper types of the generated code) are ignored.
+func resolveReferences(tm *Translations, app string, scanner *SourceScanner, references []KeyReference) map[string][]KeyReference {
+	identifiers := make(map[string]string)
+	if scanner.Identifiers != nil {
+		for _, row := range tm.GetTranslationsForApp(app) {
+			keys := []string{row.Key}
+			if row.IsPlural() {
+				keys = append(keys, row.GetSingularKey())
+			}
+			for _, key := range keys {
+				for _, identifier := range scanner.Identifiers(key) {
+					identifiers[identifier] = key
+				}
+			}
+		}
+	}
+
+	result := make(map[string][]KeyReference)
+	for _, reference := range references {
+		key := reference.Key
+		if reference.Identifier != "" {
+			var ok bool
+			if key, ok = identifiers[reference.Identifier]; !ok {
+				continue
+			}
+		}
+		result[key] = append(result[key], reference)
+	}
+	return result
+}
+
+// isReferenced reports whether a row is referenced, plural rows by their base key as well
+func isReferenced(row TranslationRow, references map[string][]KeyReference) bool {
+	if len(references[row.Key]) > 0 {
+		return true
+	}
+	return row.IsPlural() && len(references[row.GetSingularKey()]) > 0
+}
+
+// newAndroidScanner finds R.string.x/R.plurals.x in code and @string/x in resources
+func newAndroidScanner(basePath string) *SourceScanner {
+	return &SourceScanner{
+		Roots:       []string{filepath.Join(basePath, "android", "app", "src")},
+		Extensions:  []string{".kt", ".java", ".xml"},
+		ExcludeDirs: []string{"values*"},
+		KeyPatterns: []*regexp.Regexp{
+			regexp.MustCompile(`\bR\.string\.([A-Za-z0-9_]+)`),
+			regexp.MustCompile(`@string/([A-Za-z0-9_]+)`),
+		},
+		PluralPatterns: []*regexp.Regexp{
+			regexp.MustCompile(`\bR\.plurals\.([A-Za-z0-9_]+)`),
+			regexp.MustCompile(`@plurals/([A-Za-z0-9_]+)`),
+		},
+		AccessorPatterns: []*regexp.Regexp{regexp.MustCompile(`\bStrings\.([A-Za-z0-9_]+)`)},
+		Identifiers:      func(key string) []string { return []string{camelCase(key)} },
+	}
+}
+
+// newIOSScanner finds String(localized:), NSLocalizedString and LocalizedStringResource
+func newIOSScanner(basePath string) *SourceScanner {
+	return &SourceScanner{
+		Roots:      []string{filepath.Join(basePath, "ios")},
+		Extensions: []string{".swift", ".m", ".mm"},
+		KeyPatterns: []*regexp.Regexp{
+			regexp.MustCompile(`String\(\s*localized:\s*"([^"\\]+)"`),
+			regexp.MustCompile(`NSLocalizedString\(\s*@?"([^"\\]+)"`),
+			regexp.MustCompile(`LocalizedStringResource\(\s*"([^"\\]+)"`),
+		},
+		AccessorPatterns: []*regexp.Regexp{regexp.MustCompile(`\bL10n\.([A-Za-z0-9_]+)`)},
+		Identifiers:      func(key string) []string { return []string{camelCase(key)} },
+	}
+}
+
+// newWebScanner finds $t('key') and i18n.t('key'), keys built at runtime are not found
+func newWebScanner(basePath string) *SourceScanner {
+	return &SourceScanner{
+		Roots:      []string{filepath.Join(basePath, "web", "src")},
+		Extensions: []string{".svelte", ".ts", ".js"},
+		KeyPatterns: []*regexp.Regexp{
+			regexp.MustCompile("\\$t\\(\\s*['\"`]([^'\"`$]+)['\"`]"),
+			regexp.MustCompile("\\bi18n\\.t\\(\\s*['\"`]([^'\"`$]+)['\"`]"),
+		},
+	}
+}
+
+// Go lookup functions that take a key literal as their first argument, per package
+var (
+	coreLookupFunctions     = []string{"T", "Translate"}
+	templateLookupFunctions = []string{"T"}
+)
+
+// newGoScanner finds calls of the lookup functions of a package like pkg.T("key") and the
+// generated Go accessors of the package. Other functions of the package taking a string
+// literal are not key references.
+func newGoScanner(root, packageName string, lookupFunctions, extensions []string, templatePatterns ...*regexp.Regexp) *SourceScanner {
+	functions := make([]string, len(lookupFunctions))
+	for i, function := range lookupFunctions {
+		functions[i] = regexp.QuoteMeta(function)
+	}
+	lookupPattern := regexp.MustCompile(`\b` + packageName + `\.(?:` + strings.Join(functions, "|") + `)\(\s*"([^"\\]+)"`)
+
+	return &SourceScanner{
+		Roots:       []string{root},
+		Extensions:  extensions,
+		KeyPatterns: append([]*regexp.Regexp{lookupPattern}, templatePatterns...),
+		AccessorPatterns: []*regexp.Regexp{
+			regexp.MustCompile(`\b` + packageName + `\.([A-Z][A-Za-z0-9_]*)`),
+		},
+		Identifiers: func(key string) []string { return []string{"Key" + pascalCase(key), pascalCase(key)} },
+	}
+}
+
+// newFlutterScanner finds AppLocalizations.of(context).key
+func newFlutterScanner(basePath string) *SourceScanner {
+	return &SourceScanner{
+		Roots:       []string{filepath.Join(basePath, "desktop", "lib")},
+		Extensions:  []string{".dart"},
+		ExcludeDirs: []string{"l10n"},
+		KeyPatterns: []*regexp.Regexp{
+			regexp.MustCompile(`AppLocalizations\.of\(\s*\w+\s*\)!?\.([A-Za-z0-9_]+)`),
+		},
+	}
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SharedApp holds strings that are translated once and inherited by the platform apps
+const SharedApp = "shared"
+
+// parseLink splits an inheritance link "app:key", a key without app refers to the shared app
+func parseLink(link string) (string, string) {
+	if app, key, ok := strings.Cut(link, ":"); ok {
+		return app, key
+	}
+	return SharedApp, link
+}
+
+// rowIndex returns the index of a row, -1 if it does not exist
+func (tm *Translations) rowIndex(app, key string) int {
+	for i, row := range tm.Translations {
+		if row.App == app && row.Key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// linkedRowIndex returns the index of the row a row inherits from. The halves of a plural
+// pair inherit from the same half of the linked plural.
+func (tm *Translations) linkedRowIndex(row TranslationRow) int {
+	if row.Inherits == "" {
+		return -1
+	}
+	app, key := parseLink(row.Inherits)
+	if row.IsPlural() {
+		key += strings.TrimPrefix(row.Key, row.GetSingularKey())
+	}
+	return tm.rowIndex(app, key)
+}
+
+// resolvedValue returns the value of a row in a language, following its inheritance chain
+// as long as the row itself has no value
+func (tm *Translations) resolvedValue(row TranslationRow, lang string, visited map[string]bool) string {
+	if value := row.Values[lang]; value != "" || row.Inherits == "" {
+		return value
+	}
+	id := row.App + ":" + row.Key
+	if visited[id] {
+		return ""
+	}
+	visited[id] = true
+
+	i := tm.linkedRowIndex(row)
+	if i < 0 {
+		return ""
+	}
+	return tm.resolvedValue(tm.Translations[i], lang, visited)
+}
+
+// ResolveLinks returns a copy in which inheriting rows have the values of the rows they
+// inherit from, except for the languages they override
+func (tm *Translations) ResolveLinks() *Translations {
+	result := NewTranslations(tm.BasePath)
+	result.Languages = append(result.Languages, tm.Languages...)
+
+	for _, row := range tm.Translations {
+		resolved := row
+		resolved.Values = make(map[string]string, len(row.Values))
+		for lang, value := range row.Values {
+			resolved.Values[lang] = value
+		}
+
+		if row.Inherits != "" {
+			if tm.linkedRowIndex(row) < 0 {
+				fmt.Printf("Warning: %s:%s inherits %s, which does not exist\n", row.App, row.Key, row.Inherits)
+			}
+			for _, lang := range tm.Languages {
+				if value := tm.resolvedValue(row, lang, make(map[string]bool)); value != "" {
+					resolved.Values[lang] = value
+				}
+			}
+		}
+		result.Translations = append(result.Translations, resolved)
+	}
+	return result
+}
+
+// LinkKey lets a key inherit from another key, plural pairs are linked as a whole. A missing
+// shared row is created from the key's values. Values equal to the inherited ones are cleared,
+// the differing ones are kept as overrides and returned as "key [lang]: value".
+func (tm *Translations) LinkKey(app, key, link string) ([]string, error) {
+	linkApp, linkKey := parseLink(link)
+	if linkApp == app && linkKey == key {
+		return nil, fmt.Errorf("%s:%s cannot inherit from itself", app, key)
+	}
+
+	suffixes := []string{""}
+	if tm.rowIndex(app, key) < 0 {
+		suffixes = []string{".singular", ".plural"}
+	}
+
+	overrides := make([]string, 0)
+	linked := 0
+	for _, suffix := range suffixes {
+		i := tm.rowIndex(app, key+suffix)
+		if i < 0 {
+			continue
+		}
+
+		target := tm.rowIndex(linkApp, linkKey+suffix)
+		if target < 0 {
+			if linkApp != SharedApp {
+				return nil, fmt.Errorf("key %s:%s not found", linkApp, linkKey+suffix)
+			}
+			row := tm.Translations[i]
+			values := make(map[string]string, len(row.Values))
+			for lang, value := range row.Values {
+				values[lang] = value
+			}
+			tm.Translations = append(tm.Translations, TranslationRow{App: SharedApp, Key: linkKey + suffix, Comment: row.Comment, Placeholders: row.Placeholders, Values: values})
+			target = len(tm.Translations) - 1
+		}
+
+		row := &tm.Translations[i]
+		row.Inherits = linkApp + ":" + linkKey
+		for lang, value := range row.Values {
+			if value == "" {
+				continue
+			}
+			if value == tm.resolvedValue(tm.Translations[target], lang, make(map[string]bool)) {
+				row.Values[lang] = ""
+			} else {
+				overrides = append(overrides, fmt.Sprintf("%s [%s]: %s", row.Key, lang, value))
+			}
+		}
+		linked++
+	}
+
+	if linked == 0 {
+		return nil, fmt.Errorf("key %s:%s not found", app, key)
+	}
+	tm.Sort()
+	return overrides, nil
+}
+
+// UnlinkKey stops a key from inheriting, the inherited values are copied into the row
+func (tm *Translations) UnlinkKey(app, key string) error {
+	unlinked := 0
+	for _, suffix := range []string{"", ".singular", ".plural"} {
+		i := tm.rowIndex(app, key+suffix)
+		if i < 0 || tm.Translations[i].Inherits == "" {
+			continue
+		}
+
+		row := tm.Translations[i]
+		if row.Values == nil {
+			row.Values = make(map[string]string)
+		}
+		for _, lang := range tm.Languages {
+			if value := tm.resolvedValue(row, lang, make(map[string]bool)); value != "" {
+				row.Values[lang] = value
+			}
+		}
+		row.Inherits = ""
+		tm.Translations[i] = row
+		unlinked++
+	}
+
+	if unlinked == 0 {
+		return fmt.Errorf("key %s:%s does not inherit from another key", app, key)
+	}
+	return nil
+}
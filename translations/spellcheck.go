@@ -0,0 +1,262 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Default locations of the Hunspell dictionaries and the project word list
+const (
+	DefaultDictionaryDirectory = "dictionaries"
+	DefaultWordListFile        = "words.txt"
+)
+
+var (
+	// spellcheckSkipPattern matches what is not checked: markup, entities, placeholders, URLs
+	// and e-mail addresses
+	spellcheckSkipPattern = regexp.MustCompile(`<[^>]+>|&[A-Za-z0-9#]+;|` + placeholderPattern.String() + `|[a-z]+://\S+|www\.\S+|\S+@\S+\.\S+`)
+	spellcheckWordPattern = regexp.MustCompile(`[\p{L}\p{M}\p{N}]+(?:['’-][\p{L}\p{M}\p{N}]+)*`)
+)
+
+// SpellcheckOptions limits which rows and languages are checked
+type SpellcheckOptions struct {
+	Apps      []string
+	Languages []string
+}
+
+// UnknownWord is a word of a value that is neither in the dictionary nor in the word list
+type UnknownWord struct {
+	App  string
+	Key  string
+	Lang string
+	Word string
+}
+
+// WordList holds the words accepted in every language, e.g. brand names
+type WordList map[string]bool
+
+// LoadWordList loads a word list with one word per line and # comments, a missing file yields
+// an empty list
+func LoadWordList(filename string) (WordList, error) {
+	words := make(WordList)
+	file, err := os.Open(filename)
+	if os.IsNotExist(err) {
+		return words, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading word list %s: %v", filename, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if word := strings.TrimSpace(scanner.Text()); word != "" && !strings.HasPrefix(word, "#") {
+			words[word] = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading word list %s: %v", filename, err)
+	}
+	return words, nil
+}
+
+// Contains reports whether a word is in the list as written or in lower case
+func (w WordList) Contains(word string) bool {
+	return w[word] || w[strings.ToLower(word)]
+}
+
+// AddGlossary adds the words of all glossary terms and their translations
+func (w WordList) AddGlossary(glossary *Glossary) {
+	for _, term := range glossary.Terms {
+		texts := []string{term.Term}
+		for _, translation := range term.Translations {
+			texts = append(texts, translation)
+		}
+		for _, text := range texts {
+			for _, word := range spellcheckWordPattern.FindAllString(text, -1) {
+				w[strings.ToLower(word)] = true
+			}
+		}
+	}
+}
+
+// findDictionary returns the .dic file for a language: the exact locale, then the base language
+// and finally any locale of the base language (de_AT → de_AT.dic, de.dic, de_DE.dic)
+func findDictionary(directory, lang string) (string, bool) {
+	base := baseLanguage(lang)
+	candidates := []string{lang, strings.ReplaceAll(lang, "_", "-"), base}
+	for _, name := range candidates {
+		path := filepath.Join(directory, name+".dic")
+		if _, err := os.Stat(path); err == nil {
+			return path, true
+		}
+	}
+
+	matches := make([]string, 0)
+	for _, separator := range []string{"_", "-"} {
+		found, _ := filepath.Glob(filepath.Join(directory, base+separator+"*.dic"))
+		matches = append(matches, found...)
+	}
+	if len(matches) == 0 {
+		return "", false
+	}
+	sort.Strings(matches)
+	return matches[0], true
+}
+
+// LoadDictionaries loads the dictionary of every language that has one in the directory
+func LoadDictionaries(directory string, languages []string) (map[string]*HunspellDictionary, error) {
+	dictionaries := make(map[string]*HunspellDictionary)
+	loaded := make(map[string]*HunspellDictionary)
+
+	for _, lang := range languages {
+		dicFile, ok := findDictionary(directory, lang)
+		if !ok {
+			fmt.Printf("Warning: no dictionary for %s in %s, skipping it\n", lang, directory)
+			continue
+		}
+		if dictionary, ok := loaded[dicFile]; ok {
+			dictionaries[lang] = dictionary
+			continue
+		}
+
+		dictionary, err := LoadHunspellDictionary(strings.TrimSuffix(dicFile, ".dic")+".aff", dicFile)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Printf("Loaded dictionary %s for %s\n", dicFile, lang)
+		loaded[dicFile] = dictionary
+		dictionaries[lang] = dictionary
+	}
+	return dictionaries, nil
+}
+
+// spellcheckWords returns the words of a value that are checked. Of ICU messages the texts of
+// all branches are checked, words with digits and single letters are skipped.
+func spellcheckWords(value string) []string {
+	if nodes, err := ParseICU(value); err == nil && hasICUStructure(nodes) {
+		value = strings.Join(icuTexts(nodes), " ")
+	}
+	value = spellcheckSkipPattern.ReplaceAllString(value, " ")
+
+	words := make([]string, 0)
+	for _, word := range spellcheckWordPattern.FindAllString(value, -1) {
+		if len([]rune(word)) < 2 || strings.IndexFunc(word, unicode.IsDigit) >= 0 {
+			continue
+		}
+		words = append(words, word)
+	}
+	return words
+}
+
+// icuTexts returns the literal texts of an ICU message including all branches
+func icuTexts(nodes []ICUNode) []string {
+	texts := make([]string, 0)
+	for _, node := range nodes {
+		if node.Kind == ICUText {
+			texts = append(texts, node.Text)
+		}
+		for _, option := range node.Options {
+			texts = append(texts, icuTexts(option.Message)...)
+		}
+	}
+	return texts
+}
+
+// checkSpelling reports whether a word is correct. Typographic apostrophes are checked as
+// straight ones, hyphenated words are correct if the whole word or all of its parts are.
+func checkSpelling(dictionary *HunspellDictionary, words WordList, word string) bool {
+	if words.Contains(word) {
+		return true
+	}
+	if dictionary.Check(strings.ReplaceAll(word, "’", "'")) {
+		return true
+	}
+	if !strings.Contains(word, "-") {
+		return false
+	}
+	for _, part := range strings.Split(word, "-") {
+		if !words.Contains(part) && !dictionary.Check(strings.ReplaceAll(part, "’", "'")) {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckSpelling checks the words of every value against the dictionary of its language and
+// returns every unknown word once per value
+func CheckSpelling(tm *Translations, dictionaries map[string]*HunspellDictionary, words WordList, opts SpellcheckOptions) []UnknownWord {
+	unknown := make([]UnknownWord, 0)
+	for _, row := range tm.Translations {
+		if len(opts.Apps) > 0 && !slices.Contains(opts.Apps, row.App) {
+			continue
+		}
+		for _, lang := range tm.Languages {
+			dictionary, ok := dictionaries[lang]
+			if !ok || row.Values[lang] == "" {
+				continue
+			}
+			if len(opts.Languages) > 0 && !slices.Contains(opts.Languages, lang) {
+				continue
+			}
+
+			reported := make(map[string]bool)
+			for _, word := range spellcheckWords(row.Values[lang]) {
+				if reported[word] || checkSpelling(dictionary, words, word) {
+					continue
+				}
+				reported[word] = true
+				unknown = append(unknown, UnknownWord{App: row.App, Key: row.Key, Lang: lang, Word: word})
+			}
+		}
+	}
+	return unknown
+}
+
+// PrintUnknownWords prints the unknown words of every value and a summary per language, most
+// frequent first, as a starting point for the word list
+func PrintUnknownWords(unknown []UnknownWord) {
+	counts := make(map[string]map[string]int)
+	for _, u := range unknown {
+		fmt.Printf("%s:%s [%s] unknown word %q\n", u.App, u.Key, u.Lang, u.Word)
+		if counts[u.Lang] == nil {
+			counts[u.Lang] = make(map[string]int)
+		}
+		counts[u.Lang][u.Word]++
+	}
+	if len(unknown) > 0 {
+		fmt.Println()
+	}
+
+	languages := make([]string, 0, len(counts))
+	for lang := range counts {
+		languages = append(languages, lang)
+	}
+	sort.Strings(languages)
+
+	for _, lang := range languages {
+		words := make([]string, 0, len(counts[lang]))
+		for word := range counts[lang] {
+			words = append(words, word)
+		}
+		sort.Slice(words, func(i, j int) bool {
+			if counts[lang][words[i]] != counts[lang][words[j]] {
+				return counts[lang][words[i]] > counts[lang][words[j]]
+			}
+			return words[i] < words[j]
+		})
+
+		list := make([]string, len(words))
+		for i, word := range words {
+			list[i] = fmt.Sprintf("%s (%d)", word, counts[lang][word])
+		}
+		fmt.Printf("%s: %d unknown words: %s\n", lang, len(words), strings.Join(list, ", "))
+	}
+}
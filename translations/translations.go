@@ -7,10 +7,13 @@ import (
 
 // TranslationRow represents a single translation entry
 type TranslationRow struct {
-	App     string
-	Key     string
-	Comment string
-	Values  map[string]string
+	App          string
+	Key          string
+	Comment      string
+	Placeholders string // placeholder metadata as JSON, e.g. from ARB files
+	Inherits     string // app:key whose values are used unless overridden, see LinkKey
+	MaxLength    string // maximum length in characters or estimated pixels, see ParseLengthLimit
+	Values       map[string]string
 }
 
 func (tr TranslationRow) IsPlural() bool {
@@ -66,7 +69,8 @@ func (tm *Translations) EnsureLanguage(lang string) {
 	sort.Strings(tm.Languages)
 }
 
-// SetTranslation adds or updates a singular translation
+// SetTranslation adds or updates a singular translation. A value equal to the one an
+// inheriting row resolves to is left empty, so the row keeps inheriting.
 func (tm *Translations) SetTranslation(app, key, lang, value, comment string) {
 
 	// Find existing row
@@ -75,6 +79,10 @@ func (tm *Translations) SetTranslation(app, key, lang, value, comment string) {
 			if row.Values == nil {
 				row.Values = make(map[string]string)
 			}
+			if linked := tm.linkedRowIndex(row); value != "" && linked >= 0 &&
+				value == tm.resolvedValue(tm.Translations[linked], lang, make(map[string]bool)) {
+				value = ""
+			}
 			row.Values[lang] = value
 			if comment != "" && row.Comment == "" {
 				row.Comment = comment
@@ -94,6 +102,16 @@ func (tm *Translations) SetTranslation(app, key, lang, value, comment string) {
 	tm.Translations = append(tm.Translations, row)
 }
 
+// SetPlaceholders sets the placeholder metadata of an existing row
+func (tm *Translations) SetPlaceholders(app, key, placeholders string) {
+	for i, row := range tm.Translations {
+		if row.Key == key && row.App == app {
+			tm.Translations[i].Placeholders = placeholders
+			return
+		}
+	}
+}
+
 // SetPluralTranslation adds or updates a plural translation
 func (tm *Translations) SetPluralTranslation(app, key, lang, oneValue, otherValue, comment string) {
 	tm.SetTranslation(app, key+".singular", lang, oneValue, comment)
@@ -162,5 +180,18 @@ func (tm *Translations) Sort() {
 	})
 }
 
+// RemoveRows removes the given rows from the translations
+func (tm *Translations) RemoveRows(rows []TranslationRow) {
+	removed := make(map[string]bool, len(rows))
+	for _, row := range rows {
+		removed[row.App+"\x00"+row.Key] = true
+	}
 
-
+	kept := tm.Translations[:0]
+	for _, row := range tm.Translations {
+		if !removed[row.App+"\x00"+row.Key] {
+			kept = append(kept, row)
+		}
+	}
+	tm.Translations = kept
+}
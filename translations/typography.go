@@ -0,0 +1,253 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Spaces used by the French punctuation rules
+const (
+	noBreakSpace       = "\u00a0"
+	narrowNoBreakSpace = "\u202f"
+)
+
+// quoteStyle are the CLDR quotation delimiters of a locale
+type quoteStyle struct {
+	Start, End                   string
+	AlternateStart, AlternateEnd string
+}
+
+// quoteStyles per language or locale, a locale falls back to its base language (de_AT → de)
+var quoteStyles = map[string]quoteStyle{
+	"cs":    {"„", "“", "‚", "‘"},
+	"da":    {"“", "”", "‘", "’"},
+	"de":    {"„", "“", "‚", "‘"},
+	"de_CH": {"«", "»", "‹", "›"},
+	"en":    {"“", "”", "‘", "’"},
+	"es":    {"«", "»", "“", "”"},
+	"fr":    {"«", "»", "«", "»"},
+	"it":    {"«", "»", "“", "”"},
+	"ja":    {"「", "」", "『", "』"},
+	"nl":    {"“", "”", "‘", "’"},
+	"pl":    {"„", "”", "«", "»"},
+	"pt":    {"“", "”", "‘", "’"},
+	"ru":    {"«", "»", "„", "“"},
+	"zh":    {"“", "”", "‘", "’"},
+}
+
+// knownQuotePairs are the quotation delimiters recognized in any locale
+var knownQuotePairs = [][2]string{
+	{"„", "“"}, {"„", "”"}, {"“", "”"}, {"«", "»"}, {"‹", "›"}, {"‚", "‘"}, {"‘", "’"}, {"「", "」"}, {"『", "』"},
+}
+
+// markupPattern matches tags and backslash escapes, typographyProtectedPattern also entities.
+// They are never changed, except for entities of straight quotes.
+var (
+	markupPattern              = regexp.MustCompile(`<[^>]+>|\\.`)
+	typographyProtectedPattern = regexp.MustCompile(markupPattern.String() + `|&(?:[A-Za-z][A-Za-z0-9]*|#[0-9]+|#x[0-9A-Fa-f]+);`)
+)
+
+var (
+	straightQuotePattern = regexp.MustCompile(`"|&#34;|&quot;`)
+	ellipsisPattern      = regexp.MustCompile(`\.\.\.`)
+	doubleSpacePattern   = regexp.MustCompile(` {2,}`)
+)
+
+// TypographyOptions limits which rows and languages are checked
+type TypographyOptions struct {
+	Apps      []string
+	Languages []string
+	// IncludeSource also checks the English source, changing it makes every translation stale
+	IncludeSource bool
+}
+
+// typographyRule fixes one kind of typographic issue, source is the English value. Wrong
+// quotation marks are errors, the other rules are a matter of style and only warnings.
+type typographyRule struct {
+	Message  string
+	Severity string
+	Fix      func(value, source, lang string) string
+}
+
+var typographyRules = []typographyRule{
+	{"quotation marks of another locale", SeverityError, fixQuoteStyle},
+	{"straight quotation marks", SeverityError, fixStraightQuotes},
+	{`"..." instead of "…"`, SeverityWarning, func(value, source, lang string) string {
+		return mapPlainText(value, typographyProtectedPattern, func(text string) string {
+			return ellipsisPattern.ReplaceAllString(text, "…")
+		})
+	}},
+	{"missing no-break space around French punctuation", SeverityWarning, fixFrenchSpacing},
+	{"double spaces", SeverityWarning, fixDoubleSpaces},
+	{"leading or trailing whitespace differs from English", SeverityWarning, fixSurroundingWhitespace},
+}
+
+// localeQuoteStyle returns the quotation delimiters of a locale, ok is false if unknown
+func localeQuoteStyle(lang string) (quoteStyle, bool) {
+	if style, ok := quoteStyles[lang]; ok {
+		return style, true
+	}
+	style, ok := quoteStyles[baseLanguage(lang)]
+	return style, ok
+}
+
+// fixQuoteStyle replaces quotation marks of other locales by the locale's primary ones
+func fixQuoteStyle(value, source, lang string) string {
+	style, ok := localeQuoteStyle(lang)
+	if !ok {
+		return value
+	}
+	for _, pair := range knownQuotePairs {
+		if (pair[0] == style.Start && pair[1] == style.End) || (pair[0] == style.AlternateStart && pair[1] == style.AlternateEnd) {
+			continue
+		}
+		// The quoted text must not contain other quotation marks, apostrophes are allowed
+		pattern := regexp.MustCompile(regexp.QuoteMeta(pair[0]) + `([^„“”‚‘«»‹›「」『』]*?)` + regexp.QuoteMeta(pair[1]))
+		value = pattern.ReplaceAllString(value, style.Start+"${1}"+style.End)
+	}
+	return value
+}
+
+// fixStraightQuotes replaces pairs of straight double quotes, also written as entities, by the
+// locale's quotation marks. Quotes inside markup are kept, an odd number is left to a human.
+func fixStraightQuotes(value, source, lang string) string {
+	style, ok := localeQuoteStyle(lang)
+	if !ok {
+		return value
+	}
+	count := 0
+	mapPlainText(value, markupPattern, func(text string) string {
+		count += len(straightQuotePattern.FindAllString(text, -1))
+		return text
+	})
+	if count%2 != 0 {
+		return value
+	}
+
+	open := true
+	return mapPlainText(value, markupPattern, func(text string) string {
+		return straightQuotePattern.ReplaceAllStringFunc(text, func(string) string {
+			defer func() { open = !open }()
+			if open {
+				return style.Start
+			}
+			return style.End
+		})
+	})
+}
+
+// fixFrenchSpacing puts a no-break space before : ; ? ! » and after «, a narrow one before
+// ; ? !. Punctuation within words like URLs or times is left alone.
+func fixFrenchSpacing(value, source, lang string) string {
+	if baseLanguage(lang) != "fr" {
+		return value
+	}
+	return mapPlainText(value, typographyProtectedPattern, func(text string) string {
+		runes := []rune(text)
+		var sb strings.Builder
+		for i, r := range runes {
+			switch {
+			case strings.ContainsRune(":;?!»", r):
+				space := noBreakSpace
+				if strings.ContainsRune(";?!", r) {
+					space = narrowNoBreakSpace
+				}
+				written := sb.String()
+				switch {
+				case strings.HasSuffix(written, " "):
+					sb.Reset()
+					sb.WriteString(strings.TrimSuffix(written, " ") + space)
+				case strings.HasSuffix(written, noBreakSpace) || strings.HasSuffix(written, narrowNoBreakSpace):
+				case i > 0 && (unicode.IsLetter(runes[i-1]) || unicode.IsDigit(runes[i-1]) || strings.ContainsRune(")]}»…", runes[i-1])) &&
+					(r == '»' || i == len(runes)-1 || unicode.IsSpace(runes[i+1])):
+					sb.WriteString(space)
+				}
+				sb.WriteRune(r)
+			case r == '«':
+				sb.WriteRune(r)
+				if i+1 < len(runes) && (unicode.IsLetter(runes[i+1]) || unicode.IsDigit(runes[i+1])) {
+					sb.WriteString(noBreakSpace)
+				}
+			case r == ' ' && i > 0 && runes[i-1] == '«':
+				sb.WriteString(noBreakSpace)
+			default:
+				sb.WriteRune(r)
+			}
+		}
+		return sb.String()
+	})
+}
+
+// fixDoubleSpaces collapses repeated spaces within the text, surrounding whitespace is
+// checked against the source instead
+func fixDoubleSpaces(value, source, lang string) string {
+	core := strings.TrimSpace(value)
+	if core == "" {
+		return value
+	}
+	start := strings.Index(value, core)
+	fixed := mapPlainText(core, typographyProtectedPattern, func(text string) string {
+		return doubleSpacePattern.ReplaceAllString(text, " ")
+	})
+	return value[:start] + fixed + value[start+len(core):]
+}
+
+// fixSurroundingWhitespace gives a translation the leading and trailing whitespace of the
+// English source, which is usually there to be concatenated with other strings
+func fixSurroundingWhitespace(value, source, lang string) string {
+	if lang == "en" || source == "" {
+		return value
+	}
+	core := strings.TrimSpace(value)
+	sourceCore := strings.TrimSpace(source)
+	if core == "" || sourceCore == "" {
+		return value
+	}
+	sourceStart := strings.Index(source, sourceCore)
+	return source[:sourceStart] + core + source[sourceStart+len(sourceCore):]
+}
+
+// LintTypography checks every value against the typography rules of its locale and returns
+// the issues. With fix the values are corrected in place. The English source is only checked
+// with IncludeSource.
+func LintTypography(tm *Translations, opts TypographyOptions, fix bool) []ValidationIssue {
+	issues := make([]ValidationIssue, 0)
+
+	for i, row := range tm.Translations {
+		if len(opts.Apps) > 0 && !slices.Contains(opts.Apps, row.App) {
+			continue
+		}
+		source := row.Values["en"]
+
+		for _, lang := range tm.Languages {
+			if len(opts.Languages) > 0 && !slices.Contains(opts.Languages, lang) {
+				continue
+			}
+			if lang == "en" && !opts.IncludeSource {
+				continue
+			}
+			value := row.Values[lang]
+			if value == "" {
+				continue
+			}
+
+			for _, rule := range typographyRules {
+				fixed := rule.Fix(value, source, lang)
+				if fixed == value {
+					continue
+				}
+				issues = append(issues, ValidationIssue{App: row.App, Key: row.Key, Lang: lang, Severity: rule.Severity,
+					Message: fmt.Sprintf("%s: %q → %q", rule.Message, value, fixed)})
+				value = fixed
+			}
+
+			if fix {
+				tm.Translations[i].Values[lang] = value
+			}
+		}
+	}
+	return issues
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"path"
+)
+
+// FindUnusedKeys returns the rows of an app that no source reference points to. Rows matching
+// one of the ignore patterns (e.g. keys built at runtime like Month*) are never reported.
+func FindUnusedKeys(tm *Translations, app string, references map[string][]KeyReference, ignore []string) []TranslationRow {
+	result := make([]TranslationRow, 0)
+	for _, row := range tm.GetTranslationsForApp(app) {
+		if isReferenced(row, references) || matchesAny(row.Key, ignore) || (row.IsPlural() && matchesAny(row.GetSingularKey(), ignore)) {
+			continue
+		}
+		result = append(result, row)
+	}
+	return result
+}
+
+// matchesAny reports whether a key matches one of the glob patterns
+func matchesAny(key string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, key); matched {
+			return true
+		}
+	}
+	return false
+}
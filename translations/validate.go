@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"unicode/utf8"
+)
+
+// Severities of validation issues
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// ValidationIssue is a single problem found in a translation
+type ValidationIssue struct {
+	App      string
+	Key      string
+	Lang     string
+	Severity string
+	Message  string
+}
+
+// ValidationOptions limits which rows and languages are validated
+type ValidationOptions struct {
+	Apps      []string
+	Languages []string
+	// MaxLengthRatio warns about translations this much longer than English, 0 disables it
+	MaxLengthRatio float64
+}
+
+// minLengthDifference avoids length ratio warnings for short texts like "OK" → "Okay"
+const minLengthDifference = 5
+
+// ValidateTranslations checks the ICU syntax and arguments as well as the printf placeholders
+// of every translation against the English source, and the lengths against the row's limit
+// and the English length
+func ValidateTranslations(tm *Translations, opts ValidationOptions) []ValidationIssue {
+	sourceLang := "en"
+	issues := make([]ValidationIssue, 0)
+
+	for _, row := range tm.Translations {
+		if len(opts.Apps) > 0 && !slices.Contains(opts.Apps, row.App) {
+			continue
+		}
+		source := row.Values[sourceLang]
+		if source == "" {
+			continue
+		}
+
+		add := func(lang, severity, message string) {
+			issues = append(issues, ValidationIssue{App: row.App, Key: row.Key, Lang: lang, Severity: severity, Message: message})
+		}
+
+		sourceNodes, err := ParseICU(source)
+		if err != nil {
+			add(sourceLang, SeverityError, fmt.Sprintf("invalid ICU syntax: %v", err))
+		}
+
+		limit, limited := rowLengthLimit(row)
+		if limited && limit.Exceeds(source) && (len(opts.Languages) == 0 || slices.Contains(opts.Languages, sourceLang)) {
+			add(sourceLang, SeverityError, fmt.Sprintf("length %d exceeds the limit of %s", limit.Measure(source), limit))
+		}
+		sourceLength := utf8.RuneCountInString(displayText(source))
+
+		for _, lang := range tm.Languages {
+			translation := row.Values[lang]
+			if lang == sourceLang || translation == "" {
+				continue
+			}
+			if len(opts.Languages) > 0 && !slices.Contains(opts.Languages, lang) {
+				continue
+			}
+
+			if limited && limit.Exceeds(translation) {
+				add(lang, SeverityError, fmt.Sprintf("length %d exceeds the limit of %s", limit.Measure(translation), limit))
+			}
+			if length := utf8.RuneCountInString(displayText(translation)); opts.MaxLengthRatio > 0 && sourceLength > 0 &&
+				float64(length)/float64(sourceLength) > opts.MaxLengthRatio && length-sourceLength >= minLengthDifference {
+				add(lang, SeverityWarning, fmt.Sprintf("%.0f%% longer than English (%d vs %d characters)", float64(length-sourceLength)/float64(sourceLength)*100, length, sourceLength))
+			}
+
+			errors, warnings := ValidateICU(source, translation, lang)
+			for _, message := range errors {
+				add(lang, SeverityError, message)
+			}
+			for _, message := range warnings {
+				add(lang, SeverityWarning, message)
+			}
+
+			// Plural and select branches may legitimately use different placeholders
+			if sourceNodes != nil && hasICUStructure(sourceNodes) {
+				continue
+			}
+			if !placeholdersMatch(source, translation) {
+				add(lang, SeverityError, fmt.Sprintf("placeholders %v, expected %v", extractPlaceholders(translation), extractPlaceholders(source)))
+			}
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		a, b := issues[i], issues[j]
+		if a.App != b.App {
+			return a.App < b.App
+		}
+		if a.Key != b.Key {
+			return a.Key < b.Key
+		}
+		return a.Lang < b.Lang
+	})
+	return issues
+}
+
+// PrintValidationIssues prints all issues and returns the number of errors
+func PrintValidationIssues(issues []ValidationIssue) int {
+	errors := 0
+	for _, issue := range issues {
+		if issue.Severity == SeverityError {
+			errors++
+		}
+		fmt.Printf("%-7s %s:%s [%s] %s\n", issue.Severity, issue.App, issue.Key, issue.Lang, issue.Message)
+	}
+	fmt.Printf("\nFound %d errors and %d warnings\n", errors, len(issues)-errors)
+	return errors
+}